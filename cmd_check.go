@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/rules"
+)
+
+// runCheck evaluates rules against collected graph, returns exit code
+func runCheck(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	var rulesPath string
+	flags.StringVar(&rulesPath, "rules", "import-graph-rules.json", "path to rules JSON file")
+	flags.Parse(args)
+
+	config, err := rules.LoadConfig(rulesPath)
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	g, err := collector.ReadJSONL(os.Stdin)
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	fields := map[string]map[string]interface{}{}
+	for _, m := range g.Modules {
+		f, err := m.Fields()
+		if err != nil {
+			log.Println(err)
+			return 2
+		}
+		fields[m.ID] = f
+	}
+
+	report, err := config.Evaluate(g.GoModGraph(), fields, time.Now())
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+	if err := report.WriteText(os.Stdout); err != nil {
+		log.Println(err)
+		return 2
+	}
+	if report.HasErrors() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/nikolaydubina/import-graph/pkg/graphdiff"
+)
+
+// runDiff compares two graphs, returns exit code
+func runDiff(args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	var fields, format string
+	flags.StringVar(&fields, "fields", "health_score,health_score_worst_transitive", "comma separated fields to compare for modules")
+	flags.StringVar(&format, "format", "md", "format of output (md, json)")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		log.Println("expected old and new graph as arguments, each is JSONL file or git:<revision>, e.g. import-graph diff git:main graph.jsonl")
+		return 2
+	}
+
+	old, err := graphdiff.Load(flags.Arg(0))
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+	curr, err := graphdiff.Load(flags.Arg(1))
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	c := graphdiff.Comparer{}
+	if fields != "" {
+		c.Fields = strings.Split(fields, ",")
+	}
+	diff, err := c.Compare(old, curr)
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	switch format {
+	case "md":
+		err = diff.WriteMarkdown(os.Stdout, c.Fields)
+	case "json":
+		err = diff.WriteJSON(os.Stdout)
+	default:
+		log.Println("unknown format")
+		return 2
+	}
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+	return 0
+}
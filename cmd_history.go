@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/history"
+)
+
+const defaultHistoryPath = ".import-graph/history.db"
+
+// runHistory stores runs and queries them over time, returns exit code
+func runHistory(args []string) int {
+	if len(args) == 0 {
+		log.Println("expected one of subcommands: save, runs, series, regressions")
+		return 2
+	}
+
+	flags := flag.NewFlagSet("history "+args[0], flag.ExitOnError)
+	var dbPath, root, fields, format, at string
+	var since time.Duration
+	flags.StringVar(&dbPath, "db", defaultHistoryPath, "path to database")
+	switch args[0] {
+	case "save":
+		flags.StringVar(&at, "time", "", "time of run in RFC3339 (default is now)")
+	case "runs":
+		flags.StringVar(&root, "root", "", "main module (default is all)")
+	case "series":
+		flags.StringVar(&root, "root", "", "main module (default is only main module in database)")
+		flags.StringVar(&fields, "fields", strings.Join(history.DefaultSeriesFields, ","), "comma separated fields")
+		flags.StringVar(&format, "format", "tsv", "format of output (tsv, json)")
+	case "regressions":
+		flags.StringVar(&root, "root", "", "main module (default is only main module in database)")
+		flags.DurationVar(&since, "since", 30*24*time.Hour, "compare last run to last run that is at least this old")
+		flags.StringVar(&format, "format", "text", "format of output (text, json)")
+	default:
+		log.Println("unknown history subcommand")
+		return 2
+	}
+	flags.Parse(args[1:])
+
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		log.Println(err)
+		return 2
+	}
+	store, err := history.Open(dbPath)
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+	defer func() { store.Close() }()
+
+	code, err := func() (int, error) {
+		switch args[0] {
+		case "save":
+			return historySave(store, at)
+		case "runs":
+			return historyRuns(store, root)
+		case "series":
+			return historySeries(store, root, flags.Arg(0), strings.Split(fields, ","), format)
+		default:
+			return historyRegressions(store, root, since, format)
+		}
+	}()
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+	return code
+}
+
+func historySave(store *history.Store, at string) (int, error) {
+	t := time.Now()
+	if at != "" {
+		var err error
+		if t, err = time.Parse(time.RFC3339, at); err != nil {
+			return 2, fmt.Errorf("can not parse time: %w", err)
+		}
+	}
+	g, err := collector.ReadJSONL(os.Stdin)
+	if err != nil {
+		return 2, err
+	}
+	root, err := store.Save(g, t)
+	if err != nil {
+		return 2, err
+	}
+	log.Printf("saved run of %s at %s\n", root, t.Format(time.RFC3339))
+	return 0, nil
+}
+
+func historyRuns(store *history.Store, root string) (int, error) {
+	roots := []string{root}
+	if root == "" {
+		var err error
+		if roots, err = store.Roots(); err != nil {
+			return 2, err
+		}
+	}
+	for _, r := range roots {
+		times, err := store.Times(r)
+		if err != nil {
+			return 2, err
+		}
+		for _, t := range times {
+			fmt.Printf("%s\t%s\n", r, t.Format(time.RFC3339))
+		}
+	}
+	return 0, nil
+}
+
+func historySeries(store *history.Store, root, module string, fields []string, format string) (int, error) {
+	if module == "" {
+		return 2, errors.New("expected module as argument")
+	}
+	root, err := historyRoot(store, root)
+	if err != nil {
+		return 2, err
+	}
+	runs, err := store.Runs(root, time.Time{})
+	if err != nil {
+		return 2, err
+	}
+	series, err := history.Series(runs, module, fields)
+	if err != nil {
+		return 2, err
+	}
+
+	switch format {
+	case "tsv":
+		return 0, history.WriteSeriesTSV(os.Stdout, series, fields)
+	case "json":
+		return 0, json.NewEncoder(os.Stdout).Encode(series)
+	default:
+		return 2, errors.New("unknown format")
+	}
+}
+
+func historyRegressions(store *history.Store, root string, since time.Duration, format string) (int, error) {
+	root, err := historyRoot(store, root)
+	if err != nil {
+		return 2, err
+	}
+
+	last, ok, err := store.Latest(root, time.Now())
+	if err != nil {
+		return 2, fmt.Errorf("can not get last run of %s: %w", root, err)
+	}
+	if !ok {
+		return 2, fmt.Errorf("no runs of %s", root)
+	}
+	prev, ok, err := store.Latest(root, last.Time.Add(-since))
+	if err != nil {
+		return 2, fmt.Errorf("can not get run of %s older than %s before last run: %w", root, since, err)
+	}
+	if !ok {
+		return 2, fmt.Errorf("no runs of %s older than %s before last run", root, since)
+	}
+
+	regressions, err := history.DefaultRegressionChecker.Check(prev, last)
+	if err != nil {
+		return 2, err
+	}
+
+	switch format {
+	case "text":
+		err = history.WriteRegressionsText(os.Stdout, prev, last, regressions)
+	case "json":
+		err = json.NewEncoder(os.Stdout).Encode(regressions)
+	default:
+		return 2, errors.New("unknown format")
+	}
+	if err != nil {
+		return 2, err
+	}
+	if len(regressions) > 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// historyRoot returns main module, if not set it is only main module in database
+func historyRoot(store *history.Store, root string) (string, error) {
+	if root != "" {
+		return root, nil
+	}
+	roots, err := store.Roots()
+	if err != nil {
+		return "", err
+	}
+	if len(roots) != 1 {
+		return "", fmt.Errorf("database has %d main modules, set one with -root", len(roots))
+	}
+	return roots[0], nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/license"
+)
+
+// runLicense evaluates licenses in collected graph against policy, returns exit code
+func runLicense(args []string) int {
+	flags := flag.NewFlagSet("license", flag.ExitOnError)
+	var policyPath string
+	flags.StringVar(&policyPath, "policy", "license-policy.json", "path to license policy JSON file")
+	flags.Parse(args)
+
+	policy, err := license.LoadPolicy(policyPath)
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	g, err := collector.ReadJSONL(os.Stdin)
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	licenses := map[string]string{}
+	for _, m := range g.Modules {
+		if m.LicenseStats != nil {
+			licenses[m.ID] = m.LicenseStats.License
+		}
+	}
+
+	report := policy.Evaluate(g.GoModGraph(), licenses)
+	if err := report.WriteText(os.Stdout); err != nil {
+		log.Println(err)
+		return 2
+	}
+	if report.HasForbidden() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/health"
+)
+
+// runScore adds health score to every module of collected graph, returns exit code
+func runScore(args []string) int {
+	flags := flag.NewFlagSet("score", flag.ExitOnError)
+	var modelPath string
+	flags.StringVar(&modelPath, "model", "", "path to scoring model JSON file (default is built-in model)")
+	flags.Parse(args)
+
+	model := health.DefaultModel
+	if modelPath != "" {
+		m, err := health.LoadModel(modelPath)
+		if err != nil {
+			log.Println(err)
+			return 2
+		}
+		model = *m
+	}
+
+	g, err := collector.ReadJSONL(os.Stdin)
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	scores := map[string]float64{}
+	for i, m := range g.Modules {
+		m.HealthStats = nil
+		fields, err := m.Fields()
+		if err != nil {
+			log.Println(err)
+			return 2
+		}
+		if s, ok := model.Score(fields); ok {
+			g.Modules[i].HealthStats = &collector.HealthStats{Score: s.Total, Breakdown: s.Breakdown}
+			scores[m.ID] = s.Total
+		}
+	}
+
+	worst := health.WorstTransitive(g.GoModGraph(), scores)
+	for i, m := range g.Modules {
+		w, ok := worst[m.ID]
+		if !ok || m.HealthStats == nil {
+			continue
+		}
+		score := w.Score
+		g.Modules[i].HealthStats.WorstTransitiveScore = &score
+		g.Modules[i].HealthStats.WorstTransitiveModule = w.Module
+	}
+
+	if err := g.WriteJSONL(os.Stdout); err != nil {
+		log.Println(err)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/why"
+)
+
+// runWhy lists dependency paths from main module to given module, returns exit code
+func runWhy(args []string) int {
+	flags := flag.NewFlagSet("why", flag.ExitOnError)
+	var k int
+	var fields, format string
+	flags.IntVar(&k, "k", 5, "number of shortest paths to list, 0 is all paths up to "+strconv.Itoa(why.MaxPaths))
+	flags.StringVar(&fields, "fields", "health_score", "comma separated fields to show for every module on path")
+	flags.StringVar(&format, "format", "text", "format of output (text, json)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		log.Println("expected single module as argument, e.g. import-graph why golang.org/x/sys < graph.jsonl")
+		return 2
+	}
+
+	g, err := collector.ReadJSONL(os.Stdin)
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	q := why.Query{K: k}
+	if fields != "" {
+		q.Fields = strings.Split(fields, ",")
+	}
+	result, err := q.Run(g, flags.Arg(0))
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	switch format {
+	case "text":
+		err = result.WriteText(os.Stdout)
+	case "json":
+		err = result.WriteJSON(os.Stdout)
+	default:
+		log.Println("unknown format")
+		return 2
+	}
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+	return 0
+}
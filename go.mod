@@ -1,14 +1,23 @@
 module github.com/nikolaydubina/import-graph
 
-go 1.16
+go 1.18
 
 require (
 	github.com/google/go-github/v35 v35.0.0
-	github.com/kr/pretty v0.3.0 // indirect
-	github.com/rogpeppe/go-internal v1.9.0 // indirect
 	github.com/stretchr/testify v1.10.0
+	go.etcd.io/bbolt v1.3.9
 	go.uber.org/multierr v1.11.0
-	golang.org/x/crypto v0.31.0 // indirect
 	golang.org/x/oauth2 v0.26.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/google/go-querystring v1.0.0 // indirect
+	github.com/kr/pretty v0.3.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/rogpeppe/go-internal v1.9.0 // indirect
+	golang.org/x/crypto v0.31.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 )
@@ -4,18 +4,28 @@ import (
 	"context"
 	_ "embed"
 	"flag"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"regexp"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/go-github/v35/github"
 	"golang.org/x/oauth2"
 
 	"github.com/nikolaydubina/import-graph/pkg/awesomelists"
+	"github.com/nikolaydubina/import-graph/pkg/bitbucket"
+	"github.com/nikolaydubina/import-graph/pkg/buildinfo"
 	"github.com/nikolaydubina/import-graph/pkg/codecov"
 	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/dot"
+	"github.com/nikolaydubina/import-graph/pkg/gitea"
 	cgithub "github.com/nikolaydubina/import-graph/pkg/github"
+	"github.com/nikolaydubina/import-graph/pkg/gitlab"
 	"github.com/nikolaydubina/import-graph/pkg/gitstats"
 	"github.com/nikolaydubina/import-graph/pkg/gofilescanner"
 	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
@@ -23,13 +33,306 @@ import (
 	"github.com/nikolaydubina/import-graph/pkg/gotestrunner"
 	"github.com/nikolaydubina/import-graph/pkg/gourlresolver"
 	"github.com/nikolaydubina/import-graph/pkg/gourlresolver/basiccache"
+	"github.com/nikolaydubina/import-graph/pkg/graphexport"
+	"github.com/nikolaydubina/import-graph/pkg/hosting"
+	"github.com/nikolaydubina/import-graph/pkg/htmlreport"
+	"github.com/nikolaydubina/import-graph/pkg/osv"
+	"github.com/nikolaydubina/import-graph/pkg/sbom"
+	"github.com/nikolaydubina/import-graph/pkg/sourcehut"
+	"github.com/nikolaydubina/import-graph/pkg/tabular"
+	"github.com/nikolaydubina/import-graph/pkg/transform"
 )
 
 func main() {
-	var runType string
-	flag.StringVar(&runType, "i", "gomod", "type of input (e.g. gomod)")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "license":
+			os.Exit(runLicense(os.Args[2:]))
+		case "check":
+			os.Exit(runCheck(os.Args[2:]))
+		case "score":
+			os.Exit(runScore(os.Args[2:]))
+		case "why":
+			os.Exit(runWhy(os.Args[2:]))
+		case "diff":
+			os.Exit(runDiff(os.Args[2:]))
+		case "history":
+			os.Exit(runHistory(os.Args[2:]))
+		}
+	}
+	runCollect()
+}
+
+// runCollect collects stats about every module in graph, this is default command
+func runCollect() {
+	var runType, outputType, colorScheme, labels, columns, edgesPath, sortBy, focus, drop, githubHosts, gitlabHosts, hostingHosts string
+	var cluster, dropStd, collapseOwner, reduce bool
+	var depth uint
+	var githubMaxWait time.Duration
+	flag.StringVar(&runType, "i", "gomod", "type of input (gomod, sbom, binary, jsonl), binary takes paths to executables as arguments")
+	flag.StringVar(&outputType, "o", "jsonl", "type of output (jsonl, dot, html, csv, tsv, md, graphml, gexf, mermaid, cyclonedx, spdx)")
+	flag.StringVar(&colorScheme, "color", string(dot.ColorSchemeHealth), "dot color scheme (none, health, can_get, license, staleness)")
+	flag.StringVar(&labels, "labels", "", "dot comma separated fields to show in node labels")
+	flag.BoolVar(&cluster, "cluster", false, "dot group modules by repository owner")
+	flag.StringVar(&columns, "columns", "", "csv, tsv, md comma separated columns (default is all)")
+	flag.StringVar(&edgesPath, "edges", "", "csv, tsv, md path to file for edges table")
+	flag.StringVar(&sortBy, "sort", "", "comma separated fields to order modules by, prefix with - for descending, e.g. -graph_num_dependents")
+	flag.UintVar(&depth, "depth", 0, "keep modules within max distance from main module, 0 means no limit")
+	flag.StringVar(&focus, "focus", "", "keep modules matching regexp and paths from main module to them")
+	flag.StringVar(&drop, "drop", "", "drop modules matching regexp")
+	flag.BoolVar(&dropStd, "drop-std", false, "drop golang.org/x modules and go, toolchain pseudo-modules")
+	flag.BoolVar(&collapseOwner, "collapse-owner", false, "merge modules of same repository owner into single node")
+	flag.BoolVar(&reduce, "reduce", false, "remove edges implied by other paths (transitive reduction)")
+	flag.DurationVar(&githubMaxWait, "github-max-wait", time.Hour, "max time to pause until GitHub quota resets, modules get github_error_kind rate_limited if longer")
+	flag.StringVar(&githubHosts, "github-hosts", "", "path to JSON file with GitHub Enterprise hosts")
+	flag.StringVar(&gitlabHosts, "gitlab-hosts", "", "path to JSON file with self-managed GitLab hosts")
+	flag.StringVar(&hostingHosts, "hosting-hosts", "", "path to JSON file with self-hosted Gitea, Forgejo and SourceHut hosts")
 	flag.Parse()
 
+	sortKeys, err := collector.ParseSortKeys(sortBy)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	transforms, err := newTransforms(depth, focus, drop, dropStd, collapseOwner, reduce)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var g collector.Graph
+	switch runType {
+	case "gomod", "sbom", "binary":
+		var gmod gomodgraph.Graph
+		switch runType {
+		case "sbom":
+			gmod, err = sbom.Parser{}.Parse(os.Stdin)
+		case "binary":
+			gmod, err = buildinfo.Parser{}.Parse(flag.Args())
+		default:
+			gmod, err = gomodgraph.GoModGraphParser{}.Parse(os.Stdin)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		gmod = transforms.Apply(gmod)
+		goModGraphCollector, err := newGoModuleGraphStatsCollector(githubMaxWait, githubHosts, gitlabHosts, hostingHosts)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if outputType == "jsonl" && len(sortKeys) == 0 {
+			goModGraphCollector.CollectStatsWrite(gmod, os.Stdout)
+			return
+		}
+		if g, err = goModGraphCollector.CollectStats(gmod); err != nil {
+			log.Println(err)
+		}
+	case "jsonl":
+		if g, err = collector.ReadJSONL(os.Stdin); err != nil {
+			log.Fatal(err)
+		}
+		if len(transforms) > 0 {
+			g = g.Restructure(transforms.Apply(g.GoModGraph()))
+		}
+		g.SetGraphStats()
+	default:
+		log.Fatalln("unknown type of run")
+	}
+
+	if len(sortKeys) > 0 {
+		if err := g.SortModules(sortKeys); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	switch outputType {
+	case "jsonl":
+		err = g.WriteJSONL(os.Stdout)
+	case "dot":
+		if !dot.IsColorScheme(colorScheme) {
+			log.Fatalln("unknown color scheme")
+		}
+		w := dot.Writer{ColorScheme: dot.ColorSchemeEnum(colorScheme), Cluster: cluster}
+		if labels != "" {
+			w.Labels = strings.Split(labels, ",")
+		}
+		err = w.Write(os.Stdout, g)
+	case "html":
+		err = htmlreport.Writer{}.Write(os.Stdout, g)
+	case "graphml":
+		err = graphexport.GraphMLWriter{}.Write(os.Stdout, g)
+	case "gexf":
+		err = graphexport.GEXFWriter{}.Write(os.Stdout, g)
+	case "mermaid":
+		err = graphexport.MermaidWriter{MaxDepth: int(depth)}.Write(os.Stdout, g)
+	case "cyclonedx":
+		err = sbom.CycloneDXWriter{Timestamp: time.Now()}.Write(os.Stdout, g)
+	case "spdx":
+		err = sbom.SPDXWriter{Timestamp: time.Now()}.Write(os.Stdout, g)
+	case "csv", "tsv", "md":
+		w := tabular.Writer{Format: tabular.FormatEnum(outputType)}
+		if columns != "" {
+			w.Columns = strings.Split(columns, ",")
+		}
+		err = w.WriteModules(os.Stdout, g)
+		if err == nil && edgesPath != "" {
+			err = writeFile(edgesPath, func(f io.Writer) error { return w.WriteEdges(f, g) })
+		}
+	default:
+		log.Fatalln("unknown type of output")
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// newTransforms sets up transforms of graph structure in fixed order: drop, focus, depth, collapse, reduce
+func newTransforms(depth uint, focus, drop string, dropStd, collapseOwner, reduce bool) (transform.Pipeline, error) {
+	var p transform.Pipeline
+	if dropStd {
+		p = append(p, transform.Drop{Pattern: transform.StdAdjacent})
+	}
+	if drop != "" {
+		re, err := regexp.Compile(drop)
+		if err != nil {
+			return nil, fmt.Errorf("can not compile drop pattern: %w", err)
+		}
+		p = append(p, transform.Drop{Pattern: re})
+	}
+	if focus != "" {
+		re, err := regexp.Compile(focus)
+		if err != nil {
+			return nil, fmt.Errorf("can not compile focus pattern: %w", err)
+		}
+		p = append(p, transform.Focus{Pattern: re})
+	}
+	if depth > 0 {
+		p = append(p, transform.MaxDepth{Depth: depth})
+	}
+	if collapseOwner {
+		p = append(p, transform.CollapseByOwner{})
+	}
+	if reduce {
+		p = append(p, transform.TransitiveReduction{})
+	}
+	return p, nil
+}
+
+// writeFile creates file and writes to it
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// newGitHubClients sets up REST client, and GraphQL batches and responsiveness when there is token since GraphQL API requires it.
+// All wait for quota to reset up to maxWait. Host is GitHub Enterprise, or github.com when it is empty.
+func newGitHubClients(tc *http.Client, hasToken bool, maxWait time.Duration, h cgithub.Host) (cgithub.GitHubSummarizer, cgithub.ResponsivenessFetcher, error) {
+	limiter := &cgithub.RateLimitTransport{Transport: tc.Transport, MaxWait: maxWait}
+	tc = &http.Client{Transport: limiter}
+	client, graphQLURL := github.NewClient(tc), githubGraphQLURL
+	if h.Host != "" {
+		var err error
+		if client, err = h.NewClient(tc); err != nil {
+			return cgithub.GitHubSummarizer{}, cgithub.ResponsivenessFetcher{}, err
+		}
+		graphQLURL = h.GraphQLURL
+	}
+	s := cgithub.GitHubSummarizer{GitHubClient: client, RateLimiter: limiter}
+	if !hasToken {
+		return s, cgithub.ResponsivenessFetcher{}, nil
+	}
+	s.Batcher = &cgithub.GraphQLBatcher{HTTPClient: tc, URL: graphQLURL}
+	return s, cgithub.ResponsivenessFetcher{
+		HTTPClient: tc,
+		URL:        graphQLURL,
+		Window:     90 * 24 * time.Hour,
+		StaleAfter: 30 * 24 * time.Hour,
+		MaxItems:   200,
+	}, nil
+}
+
+const githubGraphQLURL = "https://api.github.com/graphql"
+
+// readGitHubHosts reads GitHub Enterprise hosts, none when path is empty
+func readGitHubHosts(path string) ([]cgithub.Host, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can not open github hosts: %w", err)
+	}
+	defer func() { f.Close() }()
+	return cgithub.ReadHosts(f)
+}
+
+// newGitLabSummarizer sets up clients of gitlab.com and of self-managed GitLab hosts from file, if path is not empty
+func newGitLabSummarizer(hostsPath string) (gitlab.Summarizer, []string, error) {
+	s := gitlab.Summarizer{Clients: map[string]gitlab.Client{
+		gitlab.PublicHost: {
+			HTTPClient: http.DefaultClient,
+			APIURL:     "https://gitlab.com/api/v4",
+			Token:      os.Getenv("GITLAB_IMPORT_GRAPH_TOKEN"),
+		},
+	}}
+	if hostsPath == "" {
+		return s, nil, nil
+	}
+	f, err := os.Open(hostsPath)
+	if err != nil {
+		return s, nil, fmt.Errorf("can not open gitlab hosts: %w", err)
+	}
+	defer func() { f.Close() }()
+	hosts, err := gitlab.ReadHosts(f)
+	if err != nil {
+		return s, nil, err
+	}
+	var hostNames []string
+	for _, h := range hosts {
+		hostNames = append(hostNames, h.Host)
+		s.Clients[h.Host] = gitlab.Client{HTTPClient: http.DefaultClient, APIURL: h.APIURL, Token: os.Getenv(h.TokenEnv)}
+	}
+	return s, hostNames, nil
+}
+
+// newHostingProviders sets up clients of codeberg.org, bitbucket.org, git.sr.ht and of self-hosted ones from file, if path is not empty
+func newHostingProviders(hostsPath string) (hosting.Providers, error) {
+	providers := hosting.Providers{
+		"codeberg.org":  gitea.Client{HTTPClient: http.DefaultClient, APIURL: "https://codeberg.org/api/v1", Token: os.Getenv("CODEBERG_IMPORT_GRAPH_TOKEN")},
+		"bitbucket.org": bitbucket.Client{HTTPClient: http.DefaultClient, APIURL: "https://api.bitbucket.org/2.0", Token: os.Getenv("BITBUCKET_IMPORT_GRAPH_TOKEN")},
+		"git.sr.ht":     sourcehut.Client{HTTPClient: http.DefaultClient, URL: "https://git.sr.ht/query", Token: os.Getenv("SRHT_IMPORT_GRAPH_TOKEN")},
+	}
+	if hostsPath == "" {
+		return providers, nil
+	}
+	f, err := os.Open(hostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("can not open hosting hosts: %w", err)
+	}
+	defer func() { f.Close() }()
+	hosts, err := hosting.ReadHosts(f)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hosts {
+		switch h.Provider {
+		case hosting.ProviderGitea:
+			providers[h.Host] = gitea.Client{HTTPClient: http.DefaultClient, APIURL: h.APIURL, Token: os.Getenv(h.TokenEnv)}
+		case hosting.ProviderSourceHut:
+			providers[h.Host] = sourcehut.Client{HTTPClient: http.DefaultClient, URL: h.APIURL, Token: os.Getenv(h.TokenEnv)}
+		}
+	}
+	return providers, nil
+}
+
+// newGoModuleGraphStatsCollector sets up all clients used to collect stats
+func newGoModuleGraphStatsCollector(githubMaxWait time.Duration, githubHostsPath, gitlabHostsPath, hostingHostsPath string) (*collector.GoModuleGraphStatsCollector, error) {
 	ctx := context.Background()
 	ghtoken := os.Getenv("GITHUB_IMPORT_GRAPH_TOKEN")
 	if ghtoken == "" {
@@ -39,46 +342,91 @@ func main() {
 		&oauth2.Token{AccessToken: ghtoken},
 	)
 	tc := oauth2.NewClient(ctx, ts)
+	ghSummarizer, ghResponsiveness, err := newGitHubClients(tc, ghtoken != "", githubMaxWait, cgithub.Host{})
+	if err != nil {
+		return nil, err
+	}
 
-	gitClient := gitstats.GitCmdLocalClient{
-		Path: ".import-graph/git-repos/",
+	codecovClient := codecov.HTTPClient{
+		HTTPClient: http.DefaultClient,
+		BaseURL:    "api.codecov.io",
 	}
 
-	switch runType {
-	case "gomod":
-		g, err := gomodgraph.GoModGraphParser{}.Parse(os.Stdin)
+	hosts, err := readGitHubHosts(githubHostsPath)
+	if err != nil {
+		return nil, err
+	}
+	var hostNames []string
+	for _, h := range hosts {
+		hostNames = append(hostNames, h.Host)
+
+		token := os.Getenv(h.TokenEnv)
+		if token == "" {
+			log.Printf("WARN: token of %s is empty, might not be able to fetch GitHub data\n", h.Host)
+		}
+		tc := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+		s, r, err := newGitHubClients(tc, token != "", githubMaxWait, h)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
-
-		goModGraphCollector := collector.GoModuleGraphStatsCollector{
-			ModuleCollector: collector.GoModuleStatsCollector{
-				URLResolver: basiccache.GoCachedResolver{
-					URLResolver: gourlresolver.GoURLResolver{HTTPClient: http.DefaultClient},
-					Storage:     sync.Map{},
-				},
-				GitStorage: gitClient,
-				GitStatsFetcher: gitstats.GitStatsFetcher{
-					GitLogFetcher: &gitClient,
-				},
-				TestRunner: gotestrunner.GoCmdTestRunner{},
-				CodecovClient: codecov.HTTPClient{
-					HTTPClient: http.DefaultClient,
-					BaseURL:    "api.codecov.io",
-				},
-				GoReportCardClient: goreportcard.GoReportCardHTTPClient{
-					HTTPClient: http.DefaultClient,
-					BaseURL:    "goreportcard.com",
-				},
-				FileScanner:         gofilescanner.FileScanner{},
-				AwesomeListsChecker: awesomelists.AwesomeListsChecker{HTTPClient: http.DefaultClient},
-				GitHubSummarizer: cgithub.GitHubSummarizer{
-					GitHubClient: github.NewClient(tc),
-				},
-			},
+		if ghSummarizer.Enterprise == nil {
+			ghSummarizer.Enterprise = map[string]*cgithub.GitHubSummarizer{}
+			ghResponsiveness.Enterprise = map[string]cgithub.ResponsivenessFetcher{}
+			codecovClient.Enterprise = map[string]codecov.HTTPClient{}
+		}
+		ghSummarizer.Enterprise[h.Host] = &s
+		ghResponsiveness.Enterprise[h.Host] = r
+		if h.CodecovURL != "" {
+			codecovClient.Enterprise[h.Host] = codecov.HTTPClient{
+				HTTPClient: http.DefaultClient,
+				BaseURL:    h.CodecovURL,
+				AppURL:     h.CodecovURL,
+				Service:    "github_enterprise",
+			}
 		}
-		goModGraphCollector.CollectStatsWrite(g, os.Stdout)
-	default:
-		log.Fatalln("unknown type of run")
 	}
+
+	glSummarizer, glHostNames, err := newGitLabSummarizer(gitlabHostsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	hostingProviders, err := newHostingProviders(hostingHostsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	gitClient := gitstats.GitCmdLocalClient{
+		Path: ".import-graph/git-repos/",
+	}
+
+	return &collector.GoModuleGraphStatsCollector{
+		ModuleCollector: collector.GoModuleStatsCollector{
+			URLResolver: basiccache.GoCachedResolver{
+				URLResolver: gourlresolver.GoURLResolver{HTTPClient: http.DefaultClient, GitHubHosts: hostNames, GitLabHosts: glHostNames, RepoHosts: hostingProviders.Hosts()},
+				Storage:     sync.Map{},
+			},
+			GitStorage: gitClient,
+			GitStatsFetcher: gitstats.GitStatsFetcher{
+				GitLogFetcher:     &gitClient,
+				GitReleaseFetcher: &gitClient,
+			},
+			TestRunner:    gotestrunner.GoCmdTestRunner{},
+			CodecovClient: codecovClient,
+			GoReportCardClient: goreportcard.GoReportCardHTTPClient{
+				HTTPClient: http.DefaultClient,
+				BaseURL:    "goreportcard.com",
+			},
+			FileScanner:         gofilescanner.FileScanner{},
+			AwesomeListsChecker: awesomelists.AwesomeListsChecker{HTTPClient: http.DefaultClient},
+			GitHubSummarizer:    ghSummarizer,
+			Responsiveness:      ghResponsiveness,
+			GitLabSummarizer:    glSummarizer,
+			Hosting:             hostingProviders,
+		},
+		OSVClient: &osv.Client{
+			HTTPClient: http.DefaultClient,
+			APIURL:     "https://api.osv.dev/v1",
+		},
+	}, nil
 }
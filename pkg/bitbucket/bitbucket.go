@@ -0,0 +1,101 @@
+// Package bitbucket collects repository stats from Bitbucket Cloud API
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nikolaydubina/import-graph/pkg/hosting"
+)
+
+// Client is client of Bitbucket Cloud
+type Client struct {
+	HTTPClient *http.Client
+	APIURL     string // e.g. https://api.bitbucket.org/2.0
+	Token      string // optional, access token, private repositories need it
+	now        func() time.Time
+}
+
+// repository is part of Bitbucket repository response that is used
+type repository struct {
+	UpdatedOn *time.Time `json:"updated_on"`
+	HasIssues bool       `json:"has_issues"`
+	Links     struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+// page is paginated response, size is total number of items
+type page struct {
+	Size *int `json:"size"`
+}
+
+// GetSummary collects summary about repository.
+// Bitbucket does not have stars and archived repositories.
+func (c Client) GetSummary(ctx context.Context, repoURL url.URL) (*hosting.Summary, error) {
+	workspace, slug := hosting.RepoPath(repoURL)
+	if workspace == "" {
+		return nil, fmt.Errorf("can not parse Bitbucket URL %s", repoURL.String())
+	}
+	path := "/repositories/" + url.PathEscape(workspace) + "/" + url.PathEscape(slug)
+
+	var r repository
+	if err := c.get(ctx, path, &r); err != nil {
+		return nil, fmt.Errorf("can not get bitbucket repository %s/%s: %w", workspace, slug, err)
+	}
+
+	s := hosting.Summary{
+		Provider: hosting.ProviderBitbucket,
+		URL:      r.Links.HTML.Href,
+	}
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+	// Bitbucket updates repository on every push
+	s.SetLastPush(r.UpdatedOn, now)
+
+	// counts are not critical, summary is still useful without them
+	var forks page
+	if err := c.get(ctx, path+"/forks?pagelen=1", &forks); err == nil {
+		s.NumForks = forks.Size
+	}
+	if r.HasIssues {
+		var issues page
+		q := url.Values{"q": {`state="new" OR state="open"`}, "pagelen": {"1"}}
+		if err := c.get(ctx, path+"/issues?"+q.Encode(), &issues); err == nil {
+			s.NumOpenIssues = issues.Size
+		}
+	}
+	return &s, nil
+}
+
+// get makes GET request to API and decodes JSON response into v
+func (c Client) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.APIURL, "/")+path, nil)
+	if err != nil {
+		return fmt.Errorf("can not make request: %w", err)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("can not make request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("can not decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package bitbucket
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/hosting"
+)
+
+func TestGetSummary(t *testing.T) {
+	responses := map[string][]byte{}
+	for _, name := range []string{"repo", "forks", "issues"} {
+		b, err := ioutil.ReadFile("testdata/" + name + "_response.json")
+		require.NoError(t, err)
+		responses[name] = b
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2.0/repositories/tildeslash/monit":
+			w.Write(responses["repo"])
+		case "/2.0/repositories/tildeslash/monit/forks":
+			w.Write(responses["forks"])
+		case "/2.0/repositories/tildeslash/monit/issues":
+			assert.Equal(t, `state="new" OR state="open"`, r.URL.Query().Get("q"))
+			w.Write(responses["issues"])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := Client{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL + "/2.0",
+		now:        func() time.Time { return time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC) },
+	}
+
+	s, err := client.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "bitbucket.org", Path: "/tildeslash/monit"})
+	require.NoError(t, err)
+
+	pushed := time.Date(2021, 9, 10, 9, 22, 17, 143628000, time.UTC)
+	forks, issues := 19, 42
+	var days uint = 20
+	assert.Equal(t, &hosting.Summary{
+		Provider:      hosting.ProviderBitbucket,
+		URL:           "https://bitbucket.org/tildeslash/monit",
+		NumForks:      &forks,
+		NumOpenIssues: &issues,
+		LastPushAt:    &pushed,
+		LastPushDays:  &days,
+	}, s)
+
+	_, err = client.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "bitbucket.org", Path: "/someone/missing"})
+	assert.Error(t, err)
+}
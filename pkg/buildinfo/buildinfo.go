@@ -0,0 +1,96 @@
+// Package buildinfo builds module graph from module information embedded into compiled Go binaries
+package buildinfo
+
+import (
+	"debug/buildinfo"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// Parser reads modules linked into binaries.
+// Binaries do not keep relationships between modules, so every dependency is edge from main module.
+type Parser struct{}
+
+// Parse reads all binaries, they have to be built from same main module, which is root of graph
+func (c Parser) Parse(paths []string) (gomodgraph.Graph, error) {
+	infos := make([]*debug.BuildInfo, 0, len(paths))
+	for _, path := range paths {
+		info, err := buildinfo.ReadFile(path)
+		if err != nil {
+			return gomodgraph.Graph{}, fmt.Errorf("can not read build info of %s: %w", path, err)
+		}
+		infos = append(infos, info)
+	}
+	return NewGraph(infos)
+}
+
+// NewGraph merges build infos into graph.
+// Binaries of different main modules are rejected, since graph would have several unrelated roots.
+func NewGraph(infos []*debug.BuildInfo) (gomodgraph.Graph, error) {
+	var g gomodgraph.Graph
+	nodeIdx := map[string]int{}
+	edgeAdded := map[gomodgraph.Edge]bool{}
+
+	addNode := func(n gomodgraph.Node) {
+		idx, ok := nodeIdx[n.ModuleName]
+		if !ok {
+			g.Modules = append(g.Modules, n)
+			nodeIdx[n.ModuleName] = len(g.Modules) - 1
+			return
+		}
+		if gomodgraph.CompareVersions(n.Version, g.Modules[idx].Version) > 0 {
+			g.Modules[idx] = n
+		}
+	}
+
+	var mainModule string
+	for _, info := range infos {
+		root := gomodgraph.Node{
+			ModuleName:    info.Main.Path,
+			Version:       info.Main.Version,
+			Sum:           info.Main.Sum,
+			BuildSettings: map[string]string{"go.version": info.GoVersion, "path": info.Path},
+		}
+		for _, s := range info.Settings {
+			root.BuildSettings[s.Key] = s.Value
+		}
+		if root.ModuleName == "" {
+			// binaries built from files outside of module have only command path
+			root.ModuleName = info.Path
+		}
+		if mainModule == "" {
+			mainModule = root.ModuleName
+		} else if root.ModuleName != mainModule {
+			return gomodgraph.Graph{}, fmt.Errorf("can not build graph of binaries of different main modules %s and %s", mainModule, root.ModuleName)
+		}
+		addNode(root)
+
+		for _, dep := range info.Deps {
+			n := NewNode(dep)
+			addNode(n)
+			if e := (gomodgraph.Edge{From: root.ModuleName, To: n.ModuleName}); !edgeAdded[e] {
+				g.Edges = append(g.Edges, e)
+				edgeAdded[e] = true
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// NewNode converts dependency in build info to node.
+// When module is replaced by other module, node is replacement since this is code that is linked.
+// When module is replaced by local directory, node is original module.
+func NewNode(dep *debug.Module) gomodgraph.Node {
+	n := gomodgraph.Node{ModuleName: dep.Path, Version: dep.Version, Sum: dep.Sum}
+	if r := dep.Replace; r != nil {
+		n.Replace = fmt.Sprintf("%s %s => %s", dep.Path, dep.Version, r.Path)
+		if r.Version != "" {
+			n.Replace += " " + r.Version
+			n.ModuleName, n.Version, n.Sum = r.Path, r.Version, r.Sum
+		}
+	}
+	return n
+}
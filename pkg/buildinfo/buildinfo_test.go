@@ -0,0 +1,97 @@
+package buildinfo
+
+import (
+	"os"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+func TestNewNode(t *testing.T) {
+	t.Run("no replace", func(t *testing.T) {
+		n := NewNode(&debug.Module{Path: "github.com/a/b", Version: "v1.0.0", Sum: "h1:abc"})
+		assert.Equal(t, gomodgraph.Node{ModuleName: "github.com/a/b", Version: "v1.0.0", Sum: "h1:abc"}, n)
+	})
+
+	t.Run("replaced by module", func(t *testing.T) {
+		n := NewNode(&debug.Module{
+			Path:    "github.com/a/b",
+			Version: "v1.0.0",
+			Replace: &debug.Module{Path: "github.com/c/b", Version: "v1.0.1", Sum: "h1:def"},
+		})
+		assert.Equal(t, gomodgraph.Node{
+			ModuleName: "github.com/c/b",
+			Version:    "v1.0.1",
+			Sum:        "h1:def",
+			Replace:    "github.com/a/b v1.0.0 => github.com/c/b v1.0.1",
+		}, n)
+	})
+
+	t.Run("replaced by local directory", func(t *testing.T) {
+		n := NewNode(&debug.Module{
+			Path:    "github.com/a/b",
+			Version: "v1.0.0",
+			Replace: &debug.Module{Path: "../b"},
+		})
+		assert.Equal(t, gomodgraph.Node{
+			ModuleName: "github.com/a/b",
+			Version:    "v1.0.0",
+			Replace:    "github.com/a/b v1.0.0 => ../b",
+		}, n)
+	})
+}
+
+func TestParse(t *testing.T) {
+	// test binary has build info of this module
+	self, err := os.Executable()
+	require.NoError(t, err)
+
+	g, err := Parser{}.Parse([]string{self, self})
+	require.NoError(t, err)
+
+	root := g.Root()
+	assert.Equal(t, "github.com/nikolaydubina/import-graph", root)
+	assert.Equal(t, root, g.Modules[0].ModuleName)
+	assert.NotEmpty(t, g.Modules[0].BuildSettings["go.version"])
+
+	seen := map[string]bool{}
+	for _, n := range g.Modules {
+		assert.False(t, seen[n.ModuleName], n.ModuleName)
+		seen[n.ModuleName] = true
+	}
+	for _, e := range g.Edges {
+		assert.Equal(t, root, e.From)
+	}
+	assert.Len(t, g.Edges, len(g.Modules)-1)
+}
+
+func TestNewGraph(t *testing.T) {
+	t.Run("same main module", func(t *testing.T) {
+		g, err := NewGraph([]*debug.BuildInfo{
+			{Path: "github.com/a/app/cmd/server", Main: debug.Module{Path: "github.com/a/app"}, Deps: []*debug.Module{{Path: "github.com/b/x", Version: "v1.0.0"}}},
+			{Path: "github.com/a/app/cmd/worker", Main: debug.Module{Path: "github.com/a/app"}, Deps: []*debug.Module{{Path: "github.com/b/x", Version: "v1.1.0"}, {Path: "github.com/b/y", Version: "v0.1.0"}}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"github.com/a/app"}, g.Roots())
+		assert.Len(t, g.Modules, 3)
+		assert.Equal(t, "v1.1.0", g.Modules[1].Version)
+		assert.Len(t, g.Edges, 2)
+	})
+
+	t.Run("different main modules", func(t *testing.T) {
+		_, err := NewGraph([]*debug.BuildInfo{
+			{Path: "github.com/a/app", Main: debug.Module{Path: "github.com/a/app"}},
+			{Path: "github.com/c/other", Main: debug.Module{Path: "github.com/c/other"}},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseNotBinary(t *testing.T) {
+	_, err := Parser{}.Parse([]string{"buildinfo.go"})
+	assert.Error(t, err)
+}
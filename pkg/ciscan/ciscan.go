@@ -0,0 +1,149 @@
+// Package ciscan inspects GitHub Actions workflows in cloned repository
+package ciscan
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Workflow is what single workflow file does
+type Workflow struct {
+	Path        string   // relative to repository, e.g. .github/workflows/test.yml
+	Name        string   // as shown in GitHub, file name when not set
+	RunsTests   bool     // e.g. go test
+	RunsLint    bool     // e.g. golangci-lint, staticcheck, go vet
+	RunsFuzzing bool     // e.g. go test -fuzz, ClusterFuzzLite
+	Actions     []string // actions and reusable workflows it uses, e.g. actions/checkout@v2
+	Dangerous   []string // patterns that let untrusted code or input run with access to secrets
+}
+
+// workflowFile is part of workflow syntax that is used
+type workflowFile struct {
+	Name string    `yaml:"name"`
+	On   yaml.Node `yaml:"on"` // string, list or map of events
+	Jobs map[string]struct {
+		Uses  string `yaml:"uses"` // reusable workflow
+		Steps []struct {
+			Uses string            `yaml:"uses"`
+			Run  string            `yaml:"run"`
+			With map[string]string `yaml:"with"`
+		} `yaml:"steps"`
+	} `yaml:"jobs"`
+}
+
+// events returns names of events that trigger workflow
+func (f workflowFile) events() map[string]bool {
+	events := map[string]bool{}
+	switch f.On.Kind {
+	case yaml.ScalarNode:
+		events[f.On.Value] = true
+	case yaml.SequenceNode:
+		for _, n := range f.On.Content {
+			events[n.Value] = true
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(f.On.Content); i += 2 {
+			events[f.On.Content[i].Value] = true
+		}
+	}
+	return events
+}
+
+var (
+	testPattern = regexp.MustCompile(`\bgo test\b|\bgotestsum\b|\bginkgo\b|\bmake test\b`)
+	lintPattern = regexp.MustCompile(`golangci-lint|staticcheck|\bgo vet\b|\brevive\b|\bgolint\b|\bgosec\b`)
+	fuzzPattern = regexp.MustCompile(`-fuzz[= ]|\bgo-fuzz\b|\bcifuzz\b|clusterfuzzlite|oss-fuzz`)
+
+	// untrustedInput is expressions that whoever opens issue or pull request controls, same as in OpenSSF Scorecard
+	untrustedInput = regexp.MustCompile(`\$\{\{[^}]*(github\.event\.(issue\.(title|body)|pull_request\.(title|body|head\.(ref|label|repo\.default_branch))|comment\.body|review\.body|review_comment\.body|pages\.[^}]*\.page_name|commits\.[^}]*\.(message|author\.(email|name))|head_commit\.(message|author\.(email|name)))|github\.head_ref)`)
+	// untrustedRef is code of pull request
+	untrustedRef = regexp.MustCompile(`github\.event\.pull_request\.head|github\.head_ref|github\.event\.workflow_run\.head`)
+)
+
+// WorkflowScanner reads GitHub Actions workflows of repository
+type WorkflowScanner struct{}
+
+// Scan returns workflows in .github/workflows of repository at path, ordered by path.
+// Files that are not valid YAML are still returned, since GitHub still has them as failing workflows.
+func (s WorkflowScanner) Scan(path string) []Workflow {
+	var workflows []Workflow
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		files, _ := filepath.Glob(filepath.Join(path, ".github", "workflows", pattern))
+		for _, f := range files {
+			workflows = append(workflows, scanFile(path, f))
+		}
+	}
+	sort.Slice(workflows, func(i, j int) bool { return workflows[i].Path < workflows[j].Path })
+	return workflows
+}
+
+func scanFile(root, path string) Workflow {
+	rel, _ := filepath.Rel(root, path)
+	w := Workflow{Path: filepath.ToSlash(rel), Name: filepath.Base(path)}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return w
+	}
+	var f workflowFile
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return w
+	}
+	if f.Name != "" {
+		w.Name = f.Name
+	}
+
+	events := f.events()
+	privileged := events["pull_request_target"] || events["workflow_run"]
+	dangerous := map[string]bool{}
+
+	for _, job := range f.Jobs {
+		if job.Uses != "" {
+			w.Actions = append(w.Actions, job.Uses)
+		}
+		for _, step := range job.Steps {
+			if privileged && strings.HasPrefix(step.Uses, "actions/checkout") && untrustedRef.MatchString(step.With["ref"]) {
+				dangerous["checkout of untrusted code in privileged workflow"] = true
+			}
+			if untrustedInput.MatchString(step.Run) || untrustedInput.MatchString(step.With["script"]) {
+				dangerous["script injection with untrusted input"] = true
+			}
+
+			text := step.Run
+			if step.Uses != "" {
+				w.Actions = append(w.Actions, step.Uses)
+				text = step.Uses
+			}
+			text = strings.ToLower(text)
+			w.RunsTests = w.RunsTests || testPattern.MatchString(text)
+			w.RunsLint = w.RunsLint || lintPattern.MatchString(text)
+			w.RunsFuzzing = w.RunsFuzzing || fuzzPattern.MatchString(text)
+		}
+	}
+	sort.Strings(w.Actions)
+	for d := range dangerous {
+		w.Dangerous = append(w.Dangerous, d)
+	}
+	sort.Strings(w.Dangerous)
+	return w
+}
+
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// IsPinned is true when action can not change without changes to workflow.
+// Actions of same repository are pinned, others have to be referenced by full commit SHA, and docker images by digest.
+func IsPinned(uses string) bool {
+	if strings.HasPrefix(uses, "./") {
+		return true
+	}
+	if strings.HasPrefix(uses, "docker://") {
+		return strings.Contains(uses, "@sha256:")
+	}
+	i := strings.LastIndex(uses, "@")
+	return i >= 0 && commitSHA.MatchString(uses[i+1:])
+}
@@ -0,0 +1,50 @@
+package ciscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScan(t *testing.T) {
+	workflows := WorkflowScanner{}.Scan("testdata/repo")
+	assert.Equal(t, []Workflow{
+		{
+			Path:    ".github/workflows/release.yaml",
+			Name:    "release.yaml",
+			Actions: []string{"docker://goreleaser/goreleaser@sha256:7d3b5bb2e23bb0d0a9b1a6a4a5a8c4a1b2ad0f2c4c3a1bd3ee9d4f8b1b2c3d4e"},
+		},
+		{
+			Path:        ".github/workflows/test.yml",
+			Name:        "Tests",
+			RunsTests:   true,
+			RunsLint:    true,
+			RunsFuzzing: true,
+			Actions: []string{
+				"./.github/workflows/release.yaml",
+				"actions/checkout@a12a3943b4bdde767164f792f33f40b04645d846",
+				"actions/checkout@a12a3943b4bdde767164f792f33f40b04645d846",
+				"actions/setup-go@v2",
+				"golangci/golangci-lint-action@v2",
+			},
+		},
+	}, workflows)
+
+	assert.Empty(t, WorkflowScanner{}.Scan("testdata"))
+}
+
+func TestIsPinned(t *testing.T) {
+	assert.True(t, IsPinned("actions/checkout@a12a3943b4bdde767164f792f33f40b04645d846"))
+	assert.True(t, IsPinned("./.github/actions/setup"))
+	assert.True(t, IsPinned("docker://alpine@sha256:4edbd2beb5f78b1014028f4fbb99f3237d9561100b6881aabbf5acce2c4f9454"))
+	assert.False(t, IsPinned("actions/checkout@v2"))
+	assert.False(t, IsPinned("docker://alpine:3.14"))
+	assert.False(t, IsPinned("github/codeql-action/init@main"))
+}
+
+func TestScanDangerous(t *testing.T) {
+	workflows := WorkflowScanner{}.Scan("testdata/dangerous")
+	assert.Len(t, workflows, 2)
+	assert.Equal(t, []string{"checkout of untrusted code in privileged workflow", "script injection with untrusted input"}, workflows[0].Dangerous)
+	assert.Empty(t, workflows[1].Dangerous)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/nikolaydubina/import-graph/pkg/github"
 )
@@ -15,7 +16,10 @@ import (
 // HTTPClient is codecov HTTP based client
 type HTTPClient struct {
 	BaseURL    string // e.g. "api.codecov.io"
+	AppURL     string // to link repository, default is "app.codecov.io"
+	Service    string // default is "github", self-hosted codecov of GitHub Enterprise uses "github_enterprise"
 	HTTPClient *http.Client
+	Enterprise map[string]HTTPClient // optional, by GitHub Enterprise host
 }
 
 // Totals in HTTP response
@@ -44,8 +48,29 @@ type RepoStats struct {
 	RepoURL       url.URL      `json:"-"`             // computed
 }
 
-func getRepoURL(owner string, repoName string) (*url.URL, error) {
-	return url.Parse(fmt.Sprintf("https://app.codecov.io/gh/%s/%s", owner, repoName))
+// short names of services in app URLs
+var appServices = map[string]string{
+	"github":            "gh",
+	"github_enterprise": "ghe",
+}
+
+func (c HTTPClient) service() string {
+	if c.Service == "" {
+		return "github"
+	}
+	return c.Service
+}
+
+func (c HTTPClient) getRepoURL(owner string, repoName string) (*url.URL, error) {
+	appURL := c.AppURL
+	if appURL == "" {
+		appURL = "app.codecov.io"
+	}
+	service, ok := appServices[c.service()]
+	if !ok {
+		service = c.service()
+	}
+	return url.Parse(fmt.Sprintf("https://%s/%s/%s/%s", appURL, service, owner, repoName))
 }
 
 // GetRepoStats makes HTTP call to codecov and parses response
@@ -53,7 +78,7 @@ func (c HTTPClient) GetRepoStats(owner string, repoName string) (*RepoStats, err
 	if owner == "" || repoName == "" {
 		return nil, errors.New("owner or repo is empty stirng")
 	}
-	url := fmt.Sprintf("https://%s/internal/github/%s/repos/%s/", c.BaseURL, owner, repoName)
+	url := fmt.Sprintf("https://%s/internal/%s/%s/repos/%s/", c.BaseURL, c.service(), owner, repoName)
 	resp, err := c.HTTPClient.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("can not get make request: %w", err)
@@ -69,15 +94,22 @@ func (c HTTPClient) GetRepoStats(owner string, repoName string) (*RepoStats, err
 	if err := json.Unmarshal(buf.Bytes(), &stats); err != nil {
 		return nil, fmt.Errorf("can not unmarshal response: %w", err)
 	}
-	if rURL, err := getRepoURL(owner, repoName); err == nil && rURL != nil {
+	if rURL, err := c.getRepoURL(owner, repoName); err == nil && rURL != nil {
 		stats.RepoURL = *rURL
 	}
 
 	return &stats, nil
 }
 
-// GetRepoStatsFromGitHubURL is convenience wrapper
+// GetRepoStatsFromGitHubURL is convenience wrapper, repositories on GitHub Enterprise use client of their host
 func (c HTTPClient) GetRepoStatsFromGitHubURL(ghURL url.URL) (*RepoStats, error) {
+	if ghURL.Host != "" && !strings.EqualFold(ghURL.Host, github.PublicHost) {
+		ec, ok := c.Enterprise[strings.ToLower(ghURL.Host)]
+		if !ok {
+			return nil, fmt.Errorf("codecov is not configured for %s", ghURL.Host)
+		}
+		c = ec
+	}
 	owner, repo := github.ParseGitHubURL(ghURL)
 	return c.GetRepoStats(owner, repo)
 }
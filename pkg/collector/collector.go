@@ -1,46 +1,154 @@
 package collector
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
 
 	"go.uber.org/multierr"
 
 	"github.com/nikolaydubina/import-graph/pkg/awesomelists"
+	"github.com/nikolaydubina/import-graph/pkg/ciscan"
 	"github.com/nikolaydubina/import-graph/pkg/codecov"
 	"github.com/nikolaydubina/import-graph/pkg/github"
+	"github.com/nikolaydubina/import-graph/pkg/gitlab"
 	"github.com/nikolaydubina/import-graph/pkg/gitstats"
 	"github.com/nikolaydubina/import-graph/pkg/gofilescanner"
 	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
 	"github.com/nikolaydubina/import-graph/pkg/goreportcard"
 	"github.com/nikolaydubina/import-graph/pkg/gotestrunner"
 	"github.com/nikolaydubina/import-graph/pkg/gourlresolver/basiccache"
+	"github.com/nikolaydubina/import-graph/pkg/graphmetrics"
+	"github.com/nikolaydubina/import-graph/pkg/hosting"
+	"github.com/nikolaydubina/import-graph/pkg/license"
+	"github.com/nikolaydubina/import-graph/pkg/osv"
 	"github.com/nikolaydubina/import-graph/pkg/scandocs"
+	"github.com/nikolaydubina/import-graph/pkg/scorecard"
 )
 
 // ModuleStats is stats about single module
 type ModuleStats struct {
 	ID         string `json:"id"` // unique key among all nodes, for Go this is module name
 	ModuleName string `json:"-"`  // this is in id anyways
+	Version    string `json:"version,omitempty"`
 
 	CanGetGitStats bool `json:"can_get_git"`
 	CanRunTests    bool `json:"can_run_tests"`
 	CanGetGitHub   bool `json:"can_get_github"`
+	CanGetGitLab   bool `json:"can_get_gitlab,omitempty"` // omitted when false, most modules are not on GitLab
+
+	GitHubErrorKind github.ErrorKindEnum `json:"github_error_kind,omitempty"` // why GitHub data can not be fetched, e.g. rate_limited
 
 	GitHubURL string `json:"github_url,omitempty"`
+	GitLabURL string `json:"gitlab_url,omitempty"`
 	GitURL    string `json:"git_url,omitempty"`
 
-	*GitStats             `json:",omitempty"`
-	*CodecovStats         `json:",omitempty"`
-	*GoTestStats          `json:",omitempty"`
-	*GoReportCardStats    `json:",omitempty"`
-	*FileStats            `json:",omitempty"`
-	*ReadmeStats          `json:",omitempty"`
-	*AwesomeLists         `json:",omitempty"`
-	*github.GitHubSummary `json:",omitempty"`
+	*GitStats              `json:",omitempty"`
+	*CodecovStats          `json:",omitempty"`
+	*GoTestStats           `json:",omitempty"`
+	*GoReportCardStats     `json:",omitempty"`
+	*FileStats             `json:",omitempty"`
+	*ReadmeStats           `json:",omitempty"`
+	*AwesomeLists          `json:",omitempty"`
+	*LicenseStats          `json:",omitempty"`
+	*HealthStats           `json:",omitempty"`
+	*VulnerabilityStats    `json:",omitempty"`
+	*BuildInfoStats        `json:",omitempty"`
+	*CIStats               `json:",omitempty"`
+	*ScorecardStats        `json:",omitempty"`
+	*GraphStats            `json:",omitempty"`
+	*github.GitHubSummary  `json:",omitempty"`
+	*github.Responsiveness `json:",omitempty"`
+	*gitlab.GitLabSummary  `json:",omitempty"`
+	*hosting.Summary       `json:",omitempty"`
+}
+
+// Fields returns flat fields of module same as in JSONL
+func (m ModuleStats) Fields() (map[string]interface{}, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("can not marshal module: %w", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, fmt.Errorf("can not unmarshal module: %w", err)
+	}
+	return fields, nil
+}
+
+// Columns returns names of all fields of ModuleStats as in JSONL, in order of declaration
+func Columns() []string {
+	return jsonFieldNames(reflect.TypeOf(ModuleStats{}))
+}
+
+// ColumnTypes returns Go types of all fields of ModuleStats by names as in JSONL, pointers are dereferenced
+func ColumnTypes() map[string]reflect.Type {
+	types := map[string]reflect.Type{}
+	walkJSONFields(reflect.TypeOf(ModuleStats{}), func(name string, t reflect.Type) { types[name] = t })
+	return types
+}
+
+// PresentColumns returns columns that have values in any of rows.
+// Known columns go first in order of declaration, followed by unknown columns in alphabetical order.
+func PresentColumns(rows []map[string]interface{}) []string {
+	present := map[string]bool{}
+	for _, row := range rows {
+		for k := range row {
+			present[k] = true
+		}
+	}
+
+	var columns []string
+	for _, col := range Columns() {
+		if present[col] {
+			columns = append(columns, col)
+			delete(present, col)
+		}
+	}
+
+	var rest []string
+	for col := range present {
+		rest = append(rest, col)
+	}
+	sort.Strings(rest)
+	return append(columns, rest...)
+}
+
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	walkJSONFields(t, func(name string, _ reflect.Type) { names = append(names, name) })
+	return names
+}
+
+// walkJSONFields calls f for every field of struct as in JSON, in order of declaration, embedded structs are flattened
+func walkJSONFields(t reflect.Type, f func(name string, t reflect.Type)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if field.Anonymous && name == "" {
+			walkJSONFields(ft, f)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		f(name, ft)
+	}
 }
 
 type Edge struct {
@@ -69,6 +177,101 @@ func (g *Graph) WriteJSONL(w io.Writer) error {
 	return finalErr
 }
 
+// ReadJSONL reads graph in same format as written by WriteJSONL
+func ReadJSONL(r io.Reader) (Graph, error) {
+	var g Graph
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var keys map[string]json.RawMessage
+		if err := json.Unmarshal(line, &keys); err != nil {
+			return g, fmt.Errorf("can not unmarshal line: %w", err)
+		}
+
+		if _, ok := keys["id"]; ok {
+			var m ModuleStats
+			if err := json.Unmarshal(line, &m); err != nil {
+				return g, fmt.Errorf("can not unmarshal module: %w", err)
+			}
+			m.ModuleName = m.ID
+			g.Modules = append(g.Modules, m)
+			continue
+		}
+
+		var e Edge
+		if err := json.Unmarshal(line, &e); err != nil {
+			return g, fmt.Errorf("can not unmarshal edge: %w", err)
+		}
+		g.Edges = append(g.Edges, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return g, fmt.Errorf("got error from scanner: %w", err)
+	}
+
+	return g, nil
+}
+
+// ModuleFields returns flat fields of every module, in same order as modules
+func (g *Graph) ModuleFields() ([]map[string]interface{}, error) {
+	rows := make([]map[string]interface{}, 0, len(g.Modules))
+	for _, m := range g.Modules {
+		fields, err := m.Fields()
+		if err != nil {
+			return nil, fmt.Errorf("can not get fields of %s: %w", m.ID, err)
+		}
+		rows = append(rows, fields)
+	}
+	return rows, nil
+}
+
+// GoModGraph returns structure of graph without stats
+func (g *Graph) GoModGraph() gomodgraph.Graph {
+	var gmod gomodgraph.Graph
+	for _, n := range g.Modules {
+		gmod.Modules = append(gmod.Modules, gomodgraph.Node{ModuleName: n.ID, Version: n.Version})
+	}
+	for _, e := range g.Edges {
+		gmod.Edges = append(gmod.Edges, gomodgraph.Edge{From: e.From, To: e.To})
+	}
+	return gmod
+}
+
+// Restructure returns graph with modules and edges of given graph.
+// Stats of modules are kept, modules that are not in this graph get only id and version.
+func (g *Graph) Restructure(gmod gomodgraph.Graph) Graph {
+	stats := make(map[string]ModuleStats, len(g.Modules))
+	for _, m := range g.Modules {
+		stats[m.ID] = m
+	}
+
+	var out Graph
+	for _, n := range gmod.Modules {
+		m, ok := stats[n.ModuleName]
+		if !ok {
+			m = ModuleStats{ID: n.ModuleName, ModuleName: n.ModuleName, Version: n.Version}
+		}
+		out.Modules = append(out.Modules, m)
+	}
+	for _, e := range gmod.Edges {
+		out.Edges = append(out.Edges, Edge{From: e.From, To: e.To})
+	}
+	return out
+}
+
+// SetGraphStats computes graph metrics of every module from current structure of graph
+func (g *Graph) SetGraphStats() {
+	metrics := graphmetrics.Compute(g.GoModGraph())
+	for i, m := range g.Modules {
+		g.Modules[i].GraphStats = NewGraphStats(metrics[m.ID])
+	}
+}
+
 // GoModuleStatsCollector is collecting all the details about single Go module
 // Does not fail if encounters errors, but still collects thoese errors.
 type GoModuleStatsCollector struct {
@@ -81,10 +284,15 @@ type GoModuleStatsCollector struct {
 	FileScanner         gofilescanner.FileScanner
 	AwesomeListsChecker awesomelists.AwesomeListsChecker
 	GitHubSummarizer    github.GitHubSummarizer
+	Responsiveness      github.ResponsivenessFetcher // optional
+	GitLabSummarizer    gitlab.Summarizer
+	Hosting             hosting.Providers // optional, by host, for repositories not on GitHub or GitLab
+	LicenseDetector     license.LocalLicenseDetector
+	WorkflowScanner     ciscan.WorkflowScanner
 }
 
 // CollectStats fetches all possible information about Go module
-func (c GoModuleStatsCollector) CollectStats(moduleName string) (ModuleStats, error) {
+func (c *GoModuleStatsCollector) CollectStats(moduleName string) (ModuleStats, error) {
 	moduleStats := ModuleStats{
 		ID:         moduleName,
 		ModuleName: moduleName,
@@ -149,6 +357,16 @@ func (c GoModuleStatsCollector) CollectStats(moduleName string) (ModuleStats, er
 		}
 	}
 
+	if wasCloned {
+		moduleStats.LicenseStats = NewLicenseStats(c.LicenseDetector.Detect(c.GitStorage.DirPath(gitURL)))
+	}
+
+	var workflows []ciscan.Workflow
+	if wasCloned {
+		workflows = c.WorkflowScanner.Scan(c.GitStorage.DirPath(gitURL))
+	}
+	var practices *github.Practices
+
 	if isMentioned, err := c.AwesomeListsChecker.IsMentioned(gitHubURL); err == nil {
 		moduleStats.AwesomeLists = &AwesomeLists{
 			IsMentioned: isMentioned,
@@ -160,10 +378,65 @@ func (c GoModuleStatsCollector) CollectStats(moduleName string) (ModuleStats, er
 	if ghSummary, err := c.GitHubSummarizer.GetSummary(context.TODO(), gitHubURL); err == nil {
 		moduleStats.GitHubSummary = ghSummary
 		moduleStats.CanGetGitHub = true
+		// runs are fetched only when there are workflows, to save quota
+		var runs *github.WorkflowRuns
+		if len(workflows) > 0 || !wasCloned {
+			if runs, err = c.GitHubSummarizer.GetWorkflowRuns(context.TODO(), gitHubURL, ghSummary.DefaultBranch); err != nil {
+				errFinal = multierr.Combine(errFinal, fmt.Errorf("can not get github workflow runs: %w", err))
+			}
+		}
+		if wasCloned || runs != nil {
+			moduleStats.CIStats = NewCIStats(workflows, runs)
+		}
+		// practices without code review are still returned when pull requests can not be fetched
+		if practices, err = c.GitHubSummarizer.GetPractices(context.TODO(), gitHubURL, ghSummary.DefaultBranch); err != nil {
+			errFinal = multierr.Combine(errFinal, fmt.Errorf("can not get github practices: %w", err))
+		}
+		if c.Responsiveness.IsConfigured(gitHubURL) {
+			if r, err := c.Responsiveness.GetResponsiveness(context.TODO(), gitHubURL); err == nil {
+				moduleStats.Responsiveness = r
+			} else {
+				errFinal = multierr.Combine(errFinal, fmt.Errorf("can not get github responsiveness: %w", err))
+			}
+		}
 	} else {
+		moduleStats.GitHubErrorKind = github.ErrorKind(err)
 		errFinal = multierr.Combine(errFinal, fmt.Errorf("can not get github stats: %w", err))
 	}
 
+	// most modules are not on GitLab, so only errors of GitLab itself are kept
+	if gitLabURL, err := c.URLResolver.ResolveGitLabURL(moduleName); err == nil {
+		moduleStats.GitLabURL = gitLabURL.String()
+		if glSummary, err := c.GitLabSummarizer.GetSummary(context.TODO(), gitLabURL); err == nil {
+			moduleStats.GitLabSummary = glSummary
+			moduleStats.CanGetGitLab = true
+		} else {
+			errFinal = multierr.Combine(errFinal, fmt.Errorf("can not get gitlab stats: %w", err))
+		}
+	}
+
+	if wasCloned || practices != nil {
+		in := scorecard.Input{Workflows: workflows, Practices: practices}
+		if wasCloned {
+			in.Path = c.GitStorage.DirPath(gitURL)
+			in.HasFuzzTests = c.FileScanner.HasFuzzTests(in.Path)
+		}
+		moduleStats.ScorecardStats = NewScorecardStats(scorecard.Run(in))
+	}
+
+	// same fields from any hosting, so that rules and reports do not depend on where module is hosted
+	if provider, repoURL, ok := c.hostingOf(moduleStats, gitURL); ok {
+		if hSummary, err := provider.GetSummary(context.TODO(), repoURL); err == nil {
+			moduleStats.Summary = hSummary
+			// license that hosting detected is used when repository can not be scanned locally
+			if moduleStats.LicenseStats == nil && hSummary.License != "" {
+				moduleStats.LicenseStats = NewLicenseStats(hSummary.License)
+			}
+		} else {
+			errFinal = multierr.Combine(errFinal, fmt.Errorf("can not get hosting stats: %w", err))
+		}
+	}
+
 	if st, err := c.TestRunner.RunModuleTets(c.GitStorage.DirPath(gitURL)); err != nil {
 		errFinal = multierr.Combine(errFinal, fmt.Errorf("can not run tests: %w", err))
 	} else {
@@ -174,9 +447,72 @@ func (c GoModuleStatsCollector) CollectStats(moduleName string) (ModuleStats, er
 	return moduleStats, errFinal
 }
 
+// hostingOf picks hosting of module and URL of repository on it.
+// GitHub and GitLab go first, their summaries are already fetched by this time.
+func (c *GoModuleStatsCollector) hostingOf(moduleStats ModuleStats, gitURL url.URL) (hosting.Provider, url.URL, bool) {
+	switch {
+	case moduleStats.GitHubSummary != nil:
+		if ghURL, err := url.Parse(moduleStats.GitHubURL); err == nil {
+			return github.HostingProvider{Summarizer: &c.GitHubSummarizer}, *ghURL, true
+		}
+	case moduleStats.GitLabSummary != nil:
+		if glURL, err := url.Parse(moduleStats.GitLabURL); err == nil {
+			return gitlab.HostingProvider{Summarizer: &c.GitLabSummarizer}, *glURL, true
+		}
+	case c.Hosting[strings.ToLower(gitURL.Host)] != nil:
+		return c.Hosting, gitURL, true
+	}
+	return nil, url.URL{}, false
+}
+
 // GoModuleGraphStatsCollector collects data about Go modules and their relationships
 type GoModuleGraphStatsCollector struct {
 	ModuleCollector GoModuleStatsCollector
+	OSVClient       *osv.Client // optional
+
+	vulnerabilities map[string][]string // by module, known only for modules with versions
+}
+
+// prefetch fetches data of all modules at once where clients support it, so that later per module calls are fast
+func (c *GoModuleGraphStatsCollector) prefetch(gmod gomodgraph.Graph) {
+	var ghURLs []url.URL
+	for _, n := range gmod.Modules {
+		if u, err := c.ModuleCollector.URLResolver.ResolveGitHubURL(n.ModuleName); err == nil {
+			ghURLs = append(ghURLs, u)
+		}
+	}
+	if err := c.ModuleCollector.GitHubSummarizer.Prefetch(context.TODO(), ghURLs); err != nil {
+		log.Printf("github prefetch: %s, falling back to REST\n", err)
+	}
+
+	// vulnerabilities are of module version, so they are known only here
+	if c.OSVClient == nil {
+		return
+	}
+	var modules []osv.Module
+	for _, n := range gmod.Modules {
+		if n.Version != "" {
+			modules = append(modules, osv.Module{Name: n.ModuleName, Version: n.Version})
+		}
+	}
+	vulns, err := c.OSVClient.GetVulnerabilities(context.TODO(), modules)
+	if err != nil {
+		log.Printf("can not get vulnerabilities: %s\n", err)
+		return
+	}
+	c.vulnerabilities = make(map[string][]string, len(vulns))
+	for m, ids := range vulns {
+		c.vulnerabilities[m.Name] = ids
+	}
+}
+
+// vulnerabilityStats returns nil when vulnerabilities of module are not known
+func (c *GoModuleGraphStatsCollector) vulnerabilityStats(moduleName string) *VulnerabilityStats {
+	ids, ok := c.vulnerabilities[moduleName]
+	if !ok {
+		return nil
+	}
+	return NewVulnerabilityStats(ids)
 }
 
 // CollectStats returns new Graph with collected data
@@ -184,9 +520,15 @@ type GoModuleGraphStatsCollector struct {
 func (c *GoModuleGraphStatsCollector) CollectStats(gmod gomodgraph.Graph) (Graph, error) {
 	var g Graph
 	var finalErr error
+	metrics := graphmetrics.Compute(gmod)
+	c.prefetch(gmod)
 
 	for i, n := range gmod.Modules {
 		moduleWithStats, err := c.ModuleCollector.CollectStats(n.ModuleName)
+		moduleWithStats.Version = n.Version
+		moduleWithStats.BuildInfoStats = NewBuildInfoStats(n)
+		moduleWithStats.VulnerabilityStats = c.vulnerabilityStats(n.ModuleName)
+		moduleWithStats.GraphStats = NewGraphStats(metrics[n.ModuleName])
 		infoStr := ""
 		if err != nil {
 			finalErr = multierr.Combine(finalErr, fmt.Errorf("can not get module stats for module %s: %w", n.ModuleName, err))
@@ -206,12 +548,18 @@ func (c *GoModuleGraphStatsCollector) CollectStats(gmod gomodgraph.Graph) (Graph
 // CollectStatsWrite is version that serializes output as soon as it is computed
 func (c *GoModuleGraphStatsCollector) CollectStatsWrite(gmod gomodgraph.Graph, w io.Writer) {
 	encoder := json.NewEncoder(w)
+	metrics := graphmetrics.Compute(gmod)
+	c.prefetch(gmod)
 
 	for _, n := range gmod.Modules {
 		m, err := c.ModuleCollector.CollectStats(n.ModuleName)
 		if err != nil {
 			log.Println(fmt.Errorf("%s got error: %w", n.ModuleName, err))
 		}
+		m.Version = n.Version
+		m.BuildInfoStats = NewBuildInfoStats(n)
+		m.VulnerabilityStats = c.vulnerabilityStats(n.ModuleName)
+		m.GraphStats = NewGraphStats(metrics[n.ModuleName])
 		if err := encoder.Encode(m); err != nil {
 			log.Println(err)
 		}
@@ -0,0 +1,54 @@
+// Package collectortest has collected graph for tests of writers
+package collectortest
+
+import "github.com/nikolaydubina/import-graph/pkg/collector"
+
+// ScriptModule is module which name needs escaping in every output format
+const ScriptModule = `example.com/</script><script>alert("z")</script>`
+
+// Graph is small collected graph, modules have different stats so that every writer has something to render
+//
+//	example.com/app -> github.com/a/x -> github.com/a/y
+//	example.com/app -> ScriptModule
+func Graph() collector.Graph {
+	return collector.Graph{
+		Modules: []collector.ModuleStats{
+			{ID: "example.com/app"},
+			{
+				ID:             "github.com/a/x",
+				Version:        "v1.2.0",
+				CanGetGitStats: true,
+				CanGetGitHub:   true,
+				CanRunTests:    true,
+				GitHubURL:      "https://github.com/a/x",
+				GitURL:         "https://github.com/a/x",
+				GitStats:       &collector.GitStats{DaysSinceLastCommit: 100, NumContributors: 12},
+				GoTestStats:    &collector.GoTestStats{HasTests: true, AllTestsPassed: true, NumPackages: 3, AvgPackageCoverage: 80},
+				LicenseStats:   collector.NewLicenseStats("MIT"),
+				HealthStats:    &collector.HealthStats{Score: 90, Breakdown: map[string]float64{"git_activity": 100, "tests": 100}},
+			},
+			{
+				ID:             "github.com/a/y",
+				Version:        "v0.1.0",
+				CanGetGitStats: true,
+				GitHubURL:      "https://github.com/a/y",
+				GitURL:         "https://github.com/a/y.git",
+				GitStats:       &collector.GitStats{DaysSinceLastCommit: 1000, NumContributors: 1},
+				LicenseStats:   collector.NewLicenseStats("AGPL-3.0"),
+				HealthStats:    &collector.HealthStats{Score: 20},
+			},
+			{
+				ID:           ScriptModule,
+				Version:      "v0.3.0",
+				CanRunTests:  true,
+				GoTestStats:  &collector.GoTestStats{HasTests: true, NumPackages: 1, AvgPackageCoverage: 42.5},
+				LicenseStats: collector.NewLicenseStats("Apache-2.0 OR MIT"),
+			},
+		},
+		Edges: []collector.Edge{
+			{From: "example.com/app", To: "github.com/a/x"},
+			{From: "example.com/app", To: ScriptModule},
+			{From: "github.com/a/x", To: "github.com/a/y"},
+		},
+	}
+}
@@ -5,11 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"time"
 
+	"github.com/nikolaydubina/import-graph/pkg/ciscan"
 	"github.com/nikolaydubina/import-graph/pkg/codecov"
+	"github.com/nikolaydubina/import-graph/pkg/github"
 	"github.com/nikolaydubina/import-graph/pkg/gitstats"
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
 	"github.com/nikolaydubina/import-graph/pkg/goreportcard"
 	"github.com/nikolaydubina/import-graph/pkg/gotestrunner"
+	"github.com/nikolaydubina/import-graph/pkg/graphmetrics"
+	"github.com/nikolaydubina/import-graph/pkg/license"
+	"github.com/nikolaydubina/import-graph/pkg/scorecard"
 )
 
 // CodecovStats is pretty printed for embedding in bigger structures
@@ -42,6 +49,10 @@ type GitStats struct {
 	LastCommit          string `json:"git_last_commit,omitempty"`  // applying formatting to days
 	DaysSinceLastCommit uint   `json:"git_last_commit_days_since"` // num full days
 	NumContributors     uint   `json:"git_num_contributors"`
+
+	LastRelease          string `json:"git_last_release,omitempty"`
+	DaysSinceLastRelease *uint  `json:"git_last_release_days_since,omitempty"`
+	NumReleasesLastYear  uint   `json:"git_num_releases_last_year"`
 }
 
 // NewGitStats look struct
@@ -49,11 +60,18 @@ func NewGitStats(r *gitstats.GitStats) *GitStats {
 	if r == nil {
 		return nil
 	}
-	return &GitStats{
+	stats := GitStats{
 		LastCommit:          r.LastCommit.Format("2006-01-02"),
 		DaysSinceLastCommit: uint(math.Floor(r.DaysSinceLastCommit)),
 		NumContributors:     r.NumContributors,
+		NumReleasesLastYear: r.NumReleasesLastYear,
+	}
+	if r.LastRelease != nil {
+		days := uint(math.Floor(time.Since(*r.LastRelease).Hours() / 24))
+		stats.LastRelease = r.LastRelease.Format("2006-01-02")
+		stats.DaysSinceLastRelease = &days
 	}
+	return &stats
 }
 
 // GoReportCardStats is pretty printed for embedding in bigger structures
@@ -114,3 +132,170 @@ type ReadmeStats struct {
 type AwesomeLists struct {
 	IsMentioned bool `json:"awesomelists_is_mentioned,omitempty"`
 }
+
+// LicenseStats is pretty printed for embedding in bigger structures
+type LicenseStats struct {
+	License  string               `json:"license,omitempty"` // SPDX expression
+	Category license.CategoryEnum `json:"license_category"`
+}
+
+// NewLicenseStats look struct
+func NewLicenseStats(spdxExpr string) *LicenseStats {
+	return &LicenseStats{
+		License:  spdxExpr,
+		Category: license.Category(spdxExpr),
+	}
+}
+
+// HealthStats is pretty printed for embedding in bigger structures
+type HealthStats struct {
+	Score                 float64            `json:"health_score"`
+	Breakdown             map[string]float64 `json:"health_score_breakdown,omitempty"`
+	WorstTransitiveScore  *float64           `json:"health_score_worst_transitive,omitempty"`
+	WorstTransitiveModule string             `json:"health_score_worst_transitive_module,omitempty"`
+}
+
+// VulnerabilityStats is pretty printed for embedding in bigger structures
+type VulnerabilityStats struct {
+	NumVulnerabilities uint     `json:"osv_num_vulnerabilities"`
+	Vulnerabilities    []string `json:"osv_vulnerabilities,omitempty"` // IDs, e.g. GO-2022-0969
+}
+
+// NewVulnerabilityStats look struct
+func NewVulnerabilityStats(ids []string) *VulnerabilityStats {
+	return &VulnerabilityStats{
+		NumVulnerabilities: uint(len(ids)),
+		Vulnerabilities:    ids,
+	}
+}
+
+// BuildInfoStats is pretty printed for embedding in bigger structures
+type BuildInfoStats struct {
+	Sum      string            `json:"buildinfo_sum,omitempty"`
+	Replace  string            `json:"buildinfo_replace,omitempty"`
+	Settings map[string]string `json:"buildinfo_settings,omitempty"`
+}
+
+// NewBuildInfoStats look struct
+func NewBuildInfoStats(n gomodgraph.Node) *BuildInfoStats {
+	if n.Sum == "" && n.Replace == "" && len(n.BuildSettings) == 0 {
+		return nil
+	}
+	return &BuildInfoStats{
+		Sum:      n.Sum,
+		Replace:  n.Replace,
+		Settings: n.BuildSettings,
+	}
+}
+
+// GraphStats is pretty printed for embedding in bigger structures
+type GraphStats struct {
+	Depth                     *uint   `json:"graph_depth,omitempty"`
+	InDegree                  uint    `json:"graph_in_degree"`
+	OutDegree                 uint    `json:"graph_out_degree"`
+	NumTransitiveDependencies uint    `json:"graph_num_transitive_dependencies"`
+	NumDependents             uint    `json:"graph_num_dependents"`
+	Betweenness               float64 `json:"graph_betweenness"`
+	IsViaSingleDirect         bool    `json:"graph_via_single_direct_dependency"`
+	ViaSingleDirect           string  `json:"graph_via_single_direct_dependency_module,omitempty"`
+}
+
+// NewGraphStats look struct
+func NewGraphStats(m graphmetrics.Metrics) *GraphStats {
+	return &GraphStats{
+		Depth:                     m.Depth,
+		InDegree:                  m.InDegree,
+		OutDegree:                 m.OutDegree,
+		NumTransitiveDependencies: m.NumTransitiveDependencies,
+		NumDependents:             m.NumDependents,
+		Betweenness:               m.Betweenness,
+		IsViaSingleDirect:         m.ViaSingleDirectDependency != "",
+		ViaSingleDirect:           m.ViaSingleDirectDependency,
+	}
+}
+
+// CIStats is pretty printed for embedding in bigger structures
+type CIStats struct {
+	HasCI              bool     `json:"has_ci"`
+	IsPassing          *bool    `json:"ci_passing,omitempty"` // latest runs on default branch, known from GitHub
+	FailingWorkflows   []string `json:"ci_failing_workflows,omitempty"`
+	LastRun            string   `json:"ci_last_run,omitempty"`
+	NumWorkflows       uint     `json:"ci_workflows"`
+	RunsTests          bool     `json:"ci_runs_tests"`
+	RunsLint           bool     `json:"ci_runs_lint"`
+	RunsFuzzing        bool     `json:"ci_runs_fuzzing"`
+	IsActionsPinned    bool     `json:"ci_actions_pinned"` // all actions are pinned by commit SHA
+	NumUnpinnedActions uint     `json:"ci_actions_unpinned"`
+}
+
+// NewCIStats from workflows in repository and their latest runs, any of them can be missing
+func NewCIStats(workflows []ciscan.Workflow, runs *github.WorkflowRuns) *CIStats {
+	stats := CIStats{
+		HasCI:        len(workflows) > 0,
+		NumWorkflows: uint(len(workflows)),
+	}
+	for _, w := range workflows {
+		stats.RunsTests = stats.RunsTests || w.RunsTests
+		stats.RunsLint = stats.RunsLint || w.RunsLint
+		stats.RunsFuzzing = stats.RunsFuzzing || w.RunsFuzzing
+		for _, a := range w.Actions {
+			if !ciscan.IsPinned(a) {
+				stats.NumUnpinnedActions++
+			}
+		}
+	}
+	stats.IsActionsPinned = stats.HasCI && stats.NumUnpinnedActions == 0
+	if runs != nil && runs.NumWorkflows > 0 {
+		passing := runs.IsPassing()
+		stats.HasCI = true
+		stats.IsPassing = &passing
+		stats.FailingWorkflows = runs.Failing
+		if runs.LastRunAt != nil {
+			stats.LastRun = runs.LastRunAt.Format("2006-01-02")
+		}
+	}
+	return &stats
+}
+
+// ScorecardStats is pretty printed for embedding in bigger structures, scores are missing when checks can not tell
+type ScorecardStats struct {
+	Score                    *float64 `json:"scorecard_score,omitempty"`
+	BranchProtection         *int     `json:"scorecard_branch_protection,omitempty"`
+	BranchProtectionReason   string   `json:"scorecard_branch_protection_reason"`
+	CodeReview               *int     `json:"scorecard_code_review,omitempty"`
+	CodeReviewReason         string   `json:"scorecard_code_review_reason"`
+	SignedReleases           *int     `json:"scorecard_signed_releases,omitempty"`
+	SignedReleasesReason     string   `json:"scorecard_signed_releases_reason"`
+	PinnedDependencies       *int     `json:"scorecard_pinned_dependencies,omitempty"`
+	PinnedDependenciesReason string   `json:"scorecard_pinned_dependencies_reason"`
+	DangerousWorkflow        *int     `json:"scorecard_dangerous_workflow,omitempty"`
+	DangerousWorkflowReason  string   `json:"scorecard_dangerous_workflow_reason"`
+	SecurityPolicy           *int     `json:"scorecard_security_policy,omitempty"`
+	SecurityPolicyReason     string   `json:"scorecard_security_policy_reason"`
+	Fuzzing                  *int     `json:"scorecard_fuzzing,omitempty"`
+	FuzzingReason            string   `json:"scorecard_fuzzing_reason"`
+}
+
+// NewScorecardStats look struct
+func NewScorecardStats(results []scorecard.Result) *ScorecardStats {
+	stats := ScorecardStats{Score: scorecard.Score(results)}
+	for _, r := range results {
+		switch r.Check {
+		case scorecard.CheckBranchProtection:
+			stats.BranchProtection, stats.BranchProtectionReason = r.Score, r.Reason
+		case scorecard.CheckCodeReview:
+			stats.CodeReview, stats.CodeReviewReason = r.Score, r.Reason
+		case scorecard.CheckSignedReleases:
+			stats.SignedReleases, stats.SignedReleasesReason = r.Score, r.Reason
+		case scorecard.CheckPinnedDependencies:
+			stats.PinnedDependencies, stats.PinnedDependenciesReason = r.Score, r.Reason
+		case scorecard.CheckDangerousWorkflow:
+			stats.DangerousWorkflow, stats.DangerousWorkflowReason = r.Score, r.Reason
+		case scorecard.CheckSecurityPolicy:
+			stats.SecurityPolicy, stats.SecurityPolicyReason = r.Score, r.Reason
+		case scorecard.CheckFuzzing:
+			stats.Fuzzing, stats.FuzzingReason = r.Score, r.Reason
+		}
+	}
+	return &stats
+}
@@ -0,0 +1,97 @@
+package collector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortKey is field by which modules are ordered
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// ParseSortKeys parses comma separated fields, field prefixed with "-" is in descending order.
+// For example "-graph_num_dependents,id".
+// Fields have to be known columns.
+func ParseSortKeys(s string) ([]SortKey, error) {
+	known := map[string]bool{}
+	for _, c := range Columns() {
+		known[c] = true
+	}
+
+	var keys []SortKey
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		k := SortKey{Field: f}
+		if strings.HasPrefix(f, "-") {
+			k = SortKey{Field: f[1:], Descending: true}
+		}
+		if !known[k.Field] {
+			return nil, fmt.Errorf("can not sort by unknown field %q", k.Field)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// SortModules orders modules by fields, modules that do not have field go last.
+// Numbers are compared as numbers, other values as strings.
+func (g *Graph) SortModules(keys []SortKey) error {
+	rows, err := g.ModuleFields()
+	if err != nil {
+		return err
+	}
+	fields := make(map[string]map[string]interface{}, len(rows))
+	for i, m := range g.Modules {
+		fields[m.ID] = rows[i]
+	}
+
+	sort.SliceStable(g.Modules, func(i, j int) bool {
+		a, b := fields[g.Modules[i].ID], fields[g.Modules[j].ID]
+		for _, k := range keys {
+			c := compareValues(a[k.Field], b[k.Field])
+			if c == 0 {
+				continue
+			}
+			// missing values are last in both orders
+			if a[k.Field] == nil || b[k.Field] == nil {
+				return a[k.Field] != nil
+			}
+			if k.Descending {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	return nil
+}
+
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	if fa, ok := a.(float64); ok {
+		if fb, ok := b.(float64); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
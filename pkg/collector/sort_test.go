@@ -0,0 +1,69 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSortKeys(t *testing.T) {
+	keys, err := ParseSortKeys("-graph_num_dependents, id,")
+	require.NoError(t, err)
+	assert.Equal(t, []SortKey{{Field: "graph_num_dependents", Descending: true}, {Field: "id"}}, keys)
+
+	keys, err = ParseSortKeys("")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	_, err = ParseSortKeys("-graph_num_dependent")
+	assert.Error(t, err)
+}
+
+func sortedIDs(t *testing.T, g Graph, s string) []string {
+	keys, err := ParseSortKeys(s)
+	require.NoError(t, err)
+	require.NoError(t, g.SortModules(keys))
+	var ids []string
+	for _, m := range g.Modules {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestSortModules(t *testing.T) {
+	graph := func() Graph {
+		return Graph{Modules: []ModuleStats{
+			{ID: "a", GitStats: &GitStats{NumContributors: 10}, LicenseStats: &LicenseStats{License: "MIT"}},
+			{ID: "b"},
+			{ID: "c", GitStats: &GitStats{NumContributors: 9}, LicenseStats: &LicenseStats{License: "Apache-2.0"}},
+			{ID: "d", GitStats: &GitStats{NumContributors: 10}, LicenseStats: &LicenseStats{License: "BSD-3-Clause"}},
+		}}
+	}
+
+	t.Run("numbers are compared as numbers", func(t *testing.T) {
+		assert.Equal(t, []string{"c", "a", "d", "b"}, sortedIDs(t, graph(), "git_num_contributors"))
+	})
+
+	t.Run("descending", func(t *testing.T) {
+		assert.Equal(t, []string{"a", "d", "c", "b"}, sortedIDs(t, graph(), "-git_num_contributors"))
+	})
+
+	t.Run("strings", func(t *testing.T) {
+		assert.Equal(t, []string{"c", "d", "a", "b"}, sortedIDs(t, graph(), "license"))
+		assert.Equal(t, []string{"a", "d", "c", "b"}, sortedIDs(t, graph(), "-license"))
+	})
+
+	t.Run("next key breaks ties", func(t *testing.T) {
+		assert.Equal(t, []string{"d", "a", "c", "b"}, sortedIDs(t, graph(), "-git_num_contributors,-id"))
+	})
+}
+
+func TestCompareValues(t *testing.T) {
+	assert.Equal(t, -1, compareValues(9.0, 10.0))
+	assert.Equal(t, 1, compareValues("9", "10"))
+	assert.Equal(t, 0, compareValues(1.0, 1.0))
+	assert.Equal(t, 1, compareValues(nil, 1.0))
+	assert.Equal(t, -1, compareValues("a", nil))
+	assert.Equal(t, 0, compareValues(nil, nil))
+}
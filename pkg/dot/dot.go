@@ -0,0 +1,178 @@
+// Package dot renders collected graph in Graphviz DOT format
+package dot
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// ColorSchemeEnum is how node color is computed from its fields
+type ColorSchemeEnum string
+
+const (
+	ColorSchemeNone      ColorSchemeEnum = "none"
+	ColorSchemeHealth    ColorSchemeEnum = "health"    // health_score
+	ColorSchemeCanGet    ColorSchemeEnum = "can_get"   // share of can_get_* and can_run_* fields that are true
+	ColorSchemeLicense   ColorSchemeEnum = "license"   // license_category
+	ColorSchemeStaleness ColorSchemeEnum = "staleness" // git_last_commit_days_since
+)
+
+// ColorSchemes lists all supported color schemes
+var ColorSchemes = []ColorSchemeEnum{ColorSchemeNone, ColorSchemeHealth, ColorSchemeCanGet, ColorSchemeLicense, ColorSchemeStaleness}
+
+// IsColorScheme checks if color scheme is supported
+func IsColorScheme(s string) bool {
+	for _, v := range ColorSchemes {
+		if string(v) == s {
+			return true
+		}
+	}
+	return false
+}
+
+const colorMissing = "#d9d9d9"
+
+var licenseColors = map[string]string{
+	"permissive":       "#7bc87b",
+	"weak-copyleft":    "#f5dc6e",
+	"strong-copyleft":  "#f5a05a",
+	"network-copyleft": "#f06464",
+	"unknown":          colorMissing,
+}
+
+// Writer renders graph to DOT
+type Writer struct {
+	ColorScheme ColorSchemeEnum
+	Labels      []string // fields to show in node label in addition to module name
+	Cluster     bool     // group modules by repository owner
+}
+
+// Write renders graph
+func (c Writer) Write(w io.Writer, g collector.Graph) error {
+	root := g.GoModGraph().Root()
+
+	var b strings.Builder
+	b.WriteString("digraph G {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("\tedge [color=\"#606060\"];\n")
+
+	clusters := map[string][]string{}
+	var clusterNames []string
+	for _, m := range g.Modules {
+		fields, err := m.Fields()
+		if err != nil {
+			return fmt.Errorf("can not get fields of %s: %w", m.ID, err)
+		}
+		node := fmt.Sprintf("%s [label=%s, fillcolor=%s];", quote(m.ID), quote(c.label(m.ID, fields)), quote(c.color(fields)))
+
+		if !c.Cluster {
+			b.WriteString("\t" + node + "\n")
+			continue
+		}
+		owner := gomodgraph.Owner(m.ID)
+		if _, ok := clusters[owner]; !ok {
+			clusterNames = append(clusterNames, owner)
+		}
+		clusters[owner] = append(clusters[owner], node)
+	}
+
+	sort.Strings(clusterNames)
+	for _, owner := range clusterNames {
+		fmt.Fprintf(&b, "\tsubgraph %s {\n", quote("cluster_"+owner))
+		fmt.Fprintf(&b, "\t\tlabel=%s;\n\t\tstyle=dashed;\n\t\tcolor=\"#a0a0a0\";\n", quote(owner))
+		for _, node := range clusters[owner] {
+			b.WriteString("\t\t" + node + "\n")
+		}
+		b.WriteString("\t}\n")
+	}
+
+	for _, e := range g.Edges {
+		style := "style=dashed"
+		if e.From == root {
+			style = "style=bold"
+		}
+		fmt.Fprintf(&b, "\t%s -> %s [%s];\n", quote(e.From), quote(e.To), style)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (c Writer) label(id string, fields map[string]interface{}) string {
+	lines := []string{id}
+	for _, f := range c.Labels {
+		if v, ok := fields[f]; ok && v != nil {
+			lines = append(lines, fmt.Sprintf("%s: %v", f, v))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (c Writer) color(fields map[string]interface{}) string {
+	switch c.ColorScheme {
+	case ColorSchemeHealth:
+		if v, ok := fields["health_score"].(float64); ok {
+			return gradient(v / 100)
+		}
+	case ColorSchemeCanGet:
+		var total, ok float64
+		for k, v := range fields {
+			if b, isBool := v.(bool); isBool && (strings.HasPrefix(k, "can_get_") || strings.HasPrefix(k, "can_run_")) {
+				total++
+				if b {
+					ok++
+				}
+			}
+		}
+		if total > 0 {
+			return gradient(ok / total)
+		}
+	case ColorSchemeLicense:
+		if v, ok := fields["license_category"].(string); ok {
+			if color, ok := licenseColors[v]; ok {
+				return color
+			}
+		}
+	case ColorSchemeStaleness:
+		if v, ok := fields["git_last_commit_days_since"].(float64); ok {
+			return gradient(1 - v/730)
+		}
+	case ColorSchemeNone, "":
+		return "white"
+	}
+	return colorMissing
+}
+
+// gradient maps value from 0 to 1 into red, yellow, green
+func gradient(v float64) string {
+	if v < 0 {
+		v = 0
+	}
+	if v > 1 {
+		v = 1
+	}
+	red, yellow, green := [3]float64{240, 100, 100}, [3]float64{245, 220, 110}, [3]float64{123, 200, 123}
+	from, to, t := red, yellow, v*2
+	if v > 0.5 {
+		from, to, t = yellow, green, (v-0.5)*2
+	}
+	var rgb [3]int
+	for i := range rgb {
+		rgb[i] = int(from[i] + (to[i]-from[i])*t)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
+
+// dotEscaper escapes string for double quoted DOT ID. Graphviz reads UTF-8 as is and does not decode Go escapes like \u00e9.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
@@ -0,0 +1,47 @@
+package dot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector/collectortest"
+	"github.com/nikolaydubina/import-graph/pkg/golden"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writer Writer
+	}{
+		{name: "none", writer: Writer{ColorScheme: ColorSchemeNone}},
+		{name: "health", writer: Writer{ColorScheme: ColorSchemeHealth}},
+		{name: "can_get", writer: Writer{ColorScheme: ColorSchemeCanGet}},
+		{name: "license", writer: Writer{ColorScheme: ColorSchemeLicense, Labels: []string{"license", "git_num_contributors"}}},
+		{name: "staleness", writer: Writer{ColorScheme: ColorSchemeStaleness, Cluster: true}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			require.NoError(t, tc.writer.Write(&b, collectortest.Graph()))
+			golden.Equal(t, tc.name+".dot", b.Bytes())
+		})
+	}
+}
+
+func TestGradient(t *testing.T) {
+	assert.Equal(t, "#f06464", gradient(-1))
+	assert.Equal(t, "#f06464", gradient(0))
+	assert.Equal(t, "#f5dc6e", gradient(0.5))
+	assert.Equal(t, "#7bc87b", gradient(1))
+	assert.Equal(t, "#7bc87b", gradient(2))
+}
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"github.com/a/x"`, quote("github.com/a/x"))
+	assert.Equal(t, `"example.com/café"`, quote("example.com/café"))
+	assert.Equal(t, `"say \"hi\" \\ bye"`, quote(`say "hi" \ bye`))
+	assert.Equal(t, `"github.com/a/x\nlicense: MIT"`, quote("github.com/a/x\nlicense: MIT"))
+}
@@ -0,0 +1,86 @@
+// Package gitea collects repository stats from Gitea and Forgejo API, e.g. codeberg.org
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nikolaydubina/import-graph/pkg/hosting"
+	"github.com/nikolaydubina/import-graph/pkg/license"
+)
+
+// Client is client of single Gitea or Forgejo instance
+type Client struct {
+	HTTPClient *http.Client
+	APIURL     string // e.g. https://codeberg.org/api/v1
+	Token      string // optional, private repositories need it
+	now        func() time.Time
+}
+
+// repository is part of Gitea repository response that is used
+type repository struct {
+	StarsCount      *int       `json:"stars_count"`
+	ForksCount      *int       `json:"forks_count"`
+	OpenIssuesCount *int       `json:"open_issues_count"`
+	HasIssues       bool       `json:"has_issues"`
+	Archived        bool       `json:"archived"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+	Licenses        []string   `json:"licenses"` // SPDX identifiers, since Gitea 1.22
+	HTMLURL         string     `json:"html_url"`
+}
+
+// GetSummary collects summary about repository
+func (c Client) GetSummary(ctx context.Context, repoURL url.URL) (*hosting.Summary, error) {
+	owner, name := hosting.RepoPath(repoURL)
+	if owner == "" {
+		return nil, fmt.Errorf("can not parse Gitea URL %s", repoURL.String())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/repos/%s/%s", strings.TrimSuffix(c.APIURL, "/"), url.PathEscape(owner), url.PathEscape(name)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("can not make request: %w", err)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("can not make request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("can not get gitea repository %s/%s: unexpected status %s", owner, name, resp.Status)
+	}
+
+	var r repository
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("can not decode response: %w", err)
+	}
+
+	s := hosting.Summary{
+		Provider:   hosting.ProviderGitea,
+		URL:        r.HTMLURL,
+		NumStars:   r.StarsCount,
+		NumForks:   r.ForksCount,
+		IsArchived: r.Archived,
+	}
+	if r.HasIssues {
+		s.NumOpenIssues = r.OpenIssuesCount
+	}
+	// several licenses can be for different files, so they are not joined into expression
+	if len(r.Licenses) == 1 {
+		s.License = license.KnownID(r.Licenses[0])
+	}
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+	// Gitea updates repository on every push
+	s.SetLastPush(r.UpdatedAt, now)
+	return &s, nil
+}
@@ -0,0 +1,58 @@
+package gitea
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/hosting"
+)
+
+func TestGetSummary(t *testing.T) {
+	repo, err := ioutil.ReadFile("testdata/repo_response.json")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/gitnex/GitNex" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		w.Write(repo)
+	}))
+	defer server.Close()
+
+	client := Client{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL + "/api/v1",
+		Token:      "secret",
+		now:        func() time.Time { return time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC) },
+	}
+
+	s, err := client.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "codeberg.org", Path: "/gitnex/GitNex.git"})
+	require.NoError(t, err)
+
+	pushed := time.Date(2021, 9, 20, 16, 2, 33, 0, time.UTC)
+	stars, forks, issues := 120, 35, 87
+	var days uint = 10
+	assert.Equal(t, &hosting.Summary{
+		Provider:      hosting.ProviderGitea,
+		URL:           "https://codeberg.org/gitnex/GitNex",
+		NumStars:      &stars,
+		NumForks:      &forks,
+		NumOpenIssues: &issues,
+		LastPushAt:    &pushed,
+		LastPushDays:  &days,
+		License:       "GPL-3.0-or-later",
+	}, s)
+
+	_, err = client.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "codeberg.org", Path: "/someone/missing"})
+	assert.Error(t, err)
+}
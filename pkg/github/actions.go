@@ -0,0 +1,65 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v35/github"
+)
+
+// WorkflowRuns is latest completed run of every workflow on branch
+type WorkflowRuns struct {
+	NumWorkflows int
+	Failing      []string // names of workflows which latest run did not succeed
+	LastRunAt    *time.Time
+}
+
+// IsPassing is true when latest runs of all workflows succeeded
+func (r WorkflowRuns) IsPassing() bool { return r.NumWorkflows > 0 && len(r.Failing) == 0 }
+
+// conclusions of runs that are not failures
+var passingConclusions = map[string]bool{
+	"success": true,
+	"neutral": true,
+	"skipped": true,
+}
+
+// GetWorkflowRuns gets latest completed run of every workflow on branch, among latest 100 runs
+func (c *GitHubSummarizer) GetWorkflowRuns(ctx context.Context, ghURL url.URL, branch string) (*WorkflowRuns, error) {
+	s, err := c.forHost(ghURL.Host)
+	if err != nil {
+		return nil, err
+	}
+	owner, repo := ParseGitHubURL(ghURL)
+	runs, _, err := s.GitHubClient.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, &github.ListWorkflowRunsOptions{
+		Branch:      branch,
+		Status:      "completed",
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not get github workflow runs %s %s: %w", owner, repo, err)
+	}
+
+	// runs are ordered from newest
+	var r WorkflowRuns
+	seen := map[int64]bool{}
+	for _, run := range runs.WorkflowRuns {
+		if seen[run.GetWorkflowID()] {
+			continue
+		}
+		seen[run.GetWorkflowID()] = true
+		r.NumWorkflows++
+		if !passingConclusions[run.GetConclusion()] {
+			r.Failing = append(r.Failing, run.GetName())
+		}
+		if run.CreatedAt != nil && (r.LastRunAt == nil || run.CreatedAt.After(*r.LastRunAt)) {
+			t := run.CreatedAt.UTC()
+			r.LastRunAt = &t
+		}
+	}
+	sort.Strings(r.Failing)
+	return &r, nil
+}
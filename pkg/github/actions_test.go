@@ -0,0 +1,41 @@
+package github
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v35/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWorkflowRuns(t *testing.T) {
+	response, err := ioutil.ReadFile("testdata/workflow_runs_response.json")
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/gin-gonic/gin/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "master", r.URL.Query().Get("branch"))
+		assert.Equal(t, "completed", r.URL.Query().Get("status"))
+		w.Write(response)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := github.NewClient(server.Client())
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	summarizer := GitHubSummarizer{GitHubClient: client}
+
+	runs, err := summarizer.GetWorkflowRuns(context.Background(), url.URL{Scheme: "https", Host: "github.com", Path: "/gin-gonic/gin"}, "master")
+	require.NoError(t, err)
+
+	lastRun := time.Date(2021, 9, 20, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, &WorkflowRuns{NumWorkflows: 3, Failing: []string{"Tests"}, LastRunAt: &lastRun}, runs)
+	assert.False(t, runs.IsPassing())
+	assert.False(t, WorkflowRuns{}.IsPassing())
+}
@@ -0,0 +1,342 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// repositoryFragment has same data as REST repository that is kept in GitHubSummary
+const repositoryFragment = `fragment repo on Repository {
+	stargazerCount
+	forkCount
+	watchers { totalCount }
+	issues(states: OPEN) { totalCount }
+	pullRequests(states: OPEN) { totalCount }
+	isArchived
+	isDisabled
+	isFork
+	parent { nameWithOwner }
+	defaultBranchRef { name }
+	licenseInfo { spdxId }
+	repositoryTopics(first: 20) { ...topics }
+	createdAt
+	pushedAt
+	homepageUrl
+	owner {
+		__typename
+		... on Organization {
+			createdAt
+			isVerified
+			requiresTwoFactorAuthentication
+			membersWithRole { totalCount }
+			hasSponsorsListing
+		}
+		... on User {
+			createdAt
+			hasSponsorsListing
+		}
+	}
+}
+
+fragment topics on RepositoryTopicConnection {
+	nodes { topic { name } }
+	pageInfo { hasNextPage endCursor }
+}`
+
+const topicsQuery = `query($owner: String!, $name: String!, $after: String) {
+	repository(owner: $owner, name: $name) {
+		repositoryTopics(first: 100, after: $after) { ...topics }
+	}
+}
+
+fragment topics on RepositoryTopicConnection {
+	nodes { topic { name } }
+	pageInfo { hasNextPage endCursor }
+}`
+
+type gqlCount struct {
+	TotalCount int `json:"totalCount"`
+}
+
+type gqlTopics struct {
+	Nodes []struct {
+		Topic struct {
+			Name string `json:"name"`
+		} `json:"topic"`
+	} `json:"nodes"`
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+}
+
+type gqlRepository struct {
+	StargazerCount int      `json:"stargazerCount"`
+	ForkCount      int      `json:"forkCount"`
+	Watchers       gqlCount `json:"watchers"`
+	Issues         gqlCount `json:"issues"`
+	PullRequests   gqlCount `json:"pullRequests"`
+	IsArchived     bool     `json:"isArchived"`
+	IsDisabled     bool     `json:"isDisabled"`
+	IsFork         bool     `json:"isFork"`
+	Parent         *struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"parent"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+	LicenseInfo *struct {
+		SPDXID string `json:"spdxId"`
+	} `json:"licenseInfo"`
+	RepositoryTopics gqlTopics  `json:"repositoryTopics"`
+	CreatedAt        *time.Time `json:"createdAt"`
+	PushedAt         *time.Time `json:"pushedAt"`
+	HomepageURL      string     `json:"homepageUrl"`
+	Owner            struct {
+		Typename                        string     `json:"__typename"`
+		CreatedAt                       *time.Time `json:"createdAt"`
+		IsVerified                      bool       `json:"isVerified"`
+		RequiresTwoFactorAuthentication *bool      `json:"requiresTwoFactorAuthentication"`
+		MembersWithRole                 *gqlCount  `json:"membersWithRole"`
+		HasSponsorsListing              *bool      `json:"hasSponsorsListing"`
+	} `json:"owner"`
+}
+
+type gqlError struct {
+	Type    string        `json:"type"`
+	Path    []interface{} `json:"path"`
+	Message string        `json:"message"`
+}
+
+type gqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []gqlError                 `json:"errors"`
+}
+
+// summary has same fields as summary from REST repository
+func (r gqlRepository) summary() *GitHubSummary {
+	stars, forks, watchers, issues := r.StargazerCount, r.ForkCount, r.Watchers.TotalCount, r.Issues.TotalCount+r.PullRequests.TotalCount
+	s := GitHubSummary{
+		NumStartsRepo: &stars,
+		NumForks:      &forks,
+		NumWatchers:   &watchers,
+		NumOpenIssues: &issues,
+		IsArchived:    r.IsArchived,
+		IsDisabled:    r.IsDisabled,
+		IsFork:        r.IsFork,
+		Homepage:      r.HomepageURL,
+		IsOwnerOrg:    r.Owner.Typename == "Organization",
+	}
+	if r.Parent != nil {
+		s.Parent = r.Parent.NameWithOwner
+	}
+	if r.DefaultBranchRef != nil {
+		s.DefaultBranch = r.DefaultBranchRef.Name
+	}
+	if r.LicenseInfo != nil && r.LicenseInfo.SPDXID != "NOASSERTION" {
+		s.License = r.LicenseInfo.SPDXID
+	}
+	for _, n := range r.RepositoryTopics.Nodes {
+		s.Topics = append(s.Topics, n.Topic.Name)
+	}
+	if r.CreatedAt != nil {
+		t := r.CreatedAt.UTC()
+		s.CreatedAt = &t
+	}
+	if r.PushedAt != nil {
+		t := r.PushedAt.UTC()
+		s.PushedAt = &t
+	}
+
+	s.OwnerSummary = &OwnerSummary{
+		IsVerified:  r.Owner.IsVerified,
+		Requires2FA: r.Owner.RequiresTwoFactorAuthentication,
+		HasSponsors: r.Owner.HasSponsorsListing,
+	}
+	if r.Owner.MembersWithRole != nil {
+		n := r.Owner.MembersWithRole.TotalCount
+		s.OwnerSummary.NumMembers = &n
+	}
+	if r.Owner.CreatedAt != nil {
+		t := r.Owner.CreatedAt.UTC()
+		s.OwnerSummary.CreatedAt = &t
+	}
+	return &s
+}
+
+// GraphQLBatcher fetches summaries of many repositories in single GraphQL request, using alias per repository.
+// Summaries are kept in memory until they are requested.
+type GraphQLBatcher struct {
+	HTTPClient *http.Client // with authentication, GitHub GraphQL API does not work without token
+	URL        string       // e.g. https://api.github.com/graphql
+	BatchSize  int          // number of repositories in single request, default is 50
+	Storage    sync.Map     // owner/name to *GitHubSummary
+}
+
+// Get returns prefetched summary
+func (c *GraphQLBatcher) Get(owner, repo string) (*GitHubSummary, bool) {
+	v, ok := c.Storage.Load(strings.ToLower(owner + "/" + repo))
+	if !ok {
+		return nil, false
+	}
+	s, ok := v.(*GitHubSummary)
+	return s, ok
+}
+
+// Prefetch fetches summaries of all repositories.
+// Repositories that can not be fetched are skipped and their errors are returned.
+func (c *GraphQLBatcher) Prefetch(ctx context.Context, repos [][2]string) error {
+	batchSize := c.BatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+
+	var errs []string
+	for start := 0; start < len(repos); start += batchSize {
+		end := start + batchSize
+		if end > len(repos) {
+			end = len(repos)
+		}
+		if err := c.fetchBatch(ctx, repos[start:end]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("can not fetch some repositories with graphql: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (c *GraphQLBatcher) fetchBatch(ctx context.Context, repos [][2]string) error {
+	var query strings.Builder
+	vars := map[string]interface{}{}
+	var params []string
+	for i, r := range repos {
+		params = append(params, fmt.Sprintf("$o%d: String!, $n%d: String!", i, i))
+		fmt.Fprintf(&query, "\tr%d: repository(owner: $o%d, name: $n%d) { ...repo }\n", i, i, i)
+		vars[fmt.Sprintf("o%d", i)] = r[0]
+		vars[fmt.Sprintf("n%d", i)] = r[1]
+	}
+	q := "query(" + strings.Join(params, ", ") + ") {\n" + query.String() + "}\n\n" + repositoryFragment
+
+	resp, err := c.do(ctx, q, vars)
+	if err != nil {
+		return err
+	}
+
+	// partial errors, e.g. repository is not found, do not fail other repositories
+	var errs []string
+	for _, e := range resp.Errors {
+		if e.Type == "RATE_LIMITED" {
+			return fmt.Errorf("%w: %s", ErrRateLimited, e.Message)
+		}
+		errs = append(errs, fmt.Sprintf("%v: %s", e.Path, e.Message))
+	}
+
+	for i, r := range repos {
+		raw, ok := resp.Data[fmt.Sprintf("r%d", i)]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+		var repo gqlRepository
+		if err := json.Unmarshal(raw, &repo); err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: can not decode: %s", r[0], r[1], err))
+			continue
+		}
+		if repo.RepositoryTopics.PageInfo.HasNextPage {
+			if err := c.fetchTopics(ctx, r[0], r[1], &repo.RepositoryTopics); err != nil {
+				errs = append(errs, fmt.Sprintf("%s/%s: %s", r[0], r[1], err))
+				continue
+			}
+		}
+		c.Storage.Store(strings.ToLower(r[0]+"/"+r[1]), repo.summary())
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// fetchTopics fetches remaining pages of topics and appends them
+func (c *GraphQLBatcher) fetchTopics(ctx context.Context, owner, name string, topics *gqlTopics) error {
+	for topics.PageInfo.HasNextPage {
+		resp, err := c.do(ctx, topicsQuery, map[string]interface{}{"owner": owner, "name": name, "after": topics.PageInfo.EndCursor})
+		if err != nil {
+			return err
+		}
+		if len(resp.Errors) > 0 {
+			return fmt.Errorf("can not get topics: %s", resp.Errors[0].Message)
+		}
+		var page struct {
+			RepositoryTopics gqlTopics `json:"repositoryTopics"`
+		}
+		if err := json.Unmarshal(resp.Data["repository"], &page); err != nil {
+			return fmt.Errorf("can not decode topics: %w", err)
+		}
+		topics.Nodes = append(topics.Nodes, page.RepositoryTopics.Nodes...)
+		topics.PageInfo = page.RepositoryTopics.PageInfo
+	}
+	return nil
+}
+
+func (c *GraphQLBatcher) do(ctx context.Context, query string, vars map[string]interface{}) (*gqlResponse, error) {
+	return doGraphQL(ctx, c.HTTPClient, c.URL, query, vars)
+}
+
+func doGraphQL(ctx context.Context, client *http.Client, endpoint string, query string, vars map[string]interface{}) (*gqlResponse, error) {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": vars})
+	if err != nil {
+		return nil, fmt.Errorf("can not encode query: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("can not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("can not do request: %w", err)
+	}
+	defer func() { res.Body.Close() }()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can not read response: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status %d: %s", res.StatusCode, string(b))
+	}
+
+	var resp gqlResponse
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, fmt.Errorf("can not decode response: %w", err)
+	}
+	return &resp, nil
+}
+
+// Repos returns owner and name of GitHub repositories, skipping duplicates and URLs that are not repositories
+func Repos(urls []url.URL) [][2]string {
+	seen := map[string]bool{}
+	var repos [][2]string
+	for _, u := range urls {
+		owner, name := ParseGitHubURL(u)
+		key := strings.ToLower(owner + "/" + name)
+		if owner == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		repos = append(repos, [2]string{owner, name})
+	}
+	return repos
+}
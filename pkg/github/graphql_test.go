@@ -0,0 +1,93 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLBatcher(t *testing.T) {
+	batchResponse, err := ioutil.ReadFile("testdata/graphql_batch_response.json")
+	require.NoError(t, err)
+	topicsResponse, err := ioutil.ReadFile("testdata/graphql_topics_response.json")
+	require.NoError(t, err)
+
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		if strings.Contains(req["query"].(string), "r0: repository") {
+			w.Write(batchResponse)
+			return
+		}
+		w.Write(topicsResponse)
+	}))
+	defer server.Close()
+
+	batcher := &GraphQLBatcher{HTTPClient: server.Client(), URL: server.URL}
+	err = batcher.Prefetch(context.Background(), Repos([]url.URL{
+		{Scheme: "https", Host: "github.com", Path: "/gin-gonic/gin"},
+		{Scheme: "https", Host: "github.com", Path: "/someone/removed"},
+		{Scheme: "https", Host: "github.com", Path: "/Gin-Gonic/gin"},
+	}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "someone/removed")
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, map[string]interface{}{"o0": "gin-gonic", "n0": "gin", "o1": "someone", "n1": "removed"}, requests[0]["variables"])
+	assert.Equal(t, map[string]interface{}{"owner": "gin-gonic", "name": "gin", "after": "Y3Vyc29yOjI="}, requests[1]["variables"])
+
+	stars, forks, watchers, issues := 61000, 7000, 1300, 620
+	created := time.Date(2014, 6, 16, 23, 57, 25, 0, time.UTC)
+	pushed := time.Date(2021, 8, 1, 10, 0, 0, 0, time.UTC)
+	ownerCreated := time.Date(2014, 6, 16, 23, 0, 0, 0, time.UTC)
+	members, sponsors := 12, false
+
+	s, ok := batcher.Get("gin-gonic", "gin")
+	require.True(t, ok)
+	assert.Equal(t, &GitHubSummary{
+		NumStartsRepo: &stars,
+		NumForks:      &forks,
+		NumWatchers:   &watchers,
+		NumOpenIssues: &issues,
+		DefaultBranch: "master",
+		License:       "MIT",
+		Topics:        []string{"go", "framework", "http"},
+		CreatedAt:     &created,
+		PushedAt:      &pushed,
+		Homepage:      "https://gin-gonic.com/",
+		IsOwnerOrg:    true,
+		OwnerSummary: &OwnerSummary{
+			IsVerified:  true,
+			NumMembers:  &members,
+			HasSponsors: &sponsors,
+			CreatedAt:   &ownerCreated,
+		},
+	}, s)
+
+	_, ok = batcher.Get("someone", "removed")
+	assert.False(t, ok)
+}
+
+func TestGraphQLBatcherBatchSize(t *testing.T) {
+	var numRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numRequests++
+		w.Write([]byte(`{"data": {}}`))
+	}))
+	defer server.Close()
+
+	batcher := &GraphQLBatcher{HTTPClient: server.Client(), URL: server.URL, BatchSize: 2}
+	require.NoError(t, batcher.Prefetch(context.Background(), [][2]string{{"a", "1"}, {"a", "2"}, {"a", "3"}}))
+	assert.Equal(t, 2, numRequests)
+}
@@ -0,0 +1,63 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v35/github"
+)
+
+// PublicHost is host of public GitHub
+const PublicHost = "github.com"
+
+// Host is GitHub Enterprise Server instance
+type Host struct {
+	Host       string `json:"host"`                  // as in module names and git URLs, e.g. github.example.com
+	APIURL     string `json:"api_url,omitempty"`     // REST API, default is https://<host>/api/v3/
+	GraphQLURL string `json:"graphql_url,omitempty"` // default is https://<host>/api/graphql
+	TokenEnv   string `json:"token_env,omitempty"`   // environment variable with token, tokens are not kept in config
+	CodecovURL string `json:"codecov_url,omitempty"` // self-hosted codecov, e.g. codecov.example.com, codecov is not checked when empty
+}
+
+// ReadHosts reads JSON array of hosts and fills in default API URLs
+func ReadHosts(r io.Reader) ([]Host, error) {
+	var hosts []Host
+	if err := json.NewDecoder(r).Decode(&hosts); err != nil {
+		return nil, fmt.Errorf("can not decode hosts: %w", err)
+	}
+	for i, h := range hosts {
+		h.Host = strings.ToLower(strings.TrimSpace(h.Host))
+		if h.Host == "" {
+			return nil, errors.New("host is empty")
+		}
+		if h.Host == PublicHost {
+			return nil, errors.New("github.com is configured by default")
+		}
+		if h.APIURL == "" {
+			h.APIURL = "https://" + h.Host + "/api/v3/"
+		}
+		if h.GraphQLURL == "" {
+			h.GraphQLURL = "https://" + h.Host + "/api/graphql"
+		}
+		hosts[i] = h
+	}
+	return hosts, nil
+}
+
+// NewClient makes REST client for host
+func (h Host) NewClient(httpClient *http.Client) (*github.Client, error) {
+	c, err := github.NewEnterpriseClient(h.APIURL, h.APIURL, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("can not make client for %s: %w", h.Host, err)
+	}
+	return c, nil
+}
+
+// isPublic is true for github.com and URLs without host
+func isPublic(host string) bool {
+	return host == "" || strings.EqualFold(host, PublicHost)
+}
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadHosts(t *testing.T) {
+	hosts, err := ReadHosts(strings.NewReader(`[
+		{"host": "GitHub.Example.com", "token_env": "GHE_TOKEN"},
+		{"host": "ghe.internal", "api_url": "https://api.ghe.internal/", "graphql_url": "https://api.ghe.internal/graphql", "codecov_url": "codecov.internal"}
+	]`))
+	require.NoError(t, err)
+	assert.Equal(t, []Host{
+		{
+			Host:       "github.example.com",
+			APIURL:     "https://github.example.com/api/v3/",
+			GraphQLURL: "https://github.example.com/api/graphql",
+			TokenEnv:   "GHE_TOKEN",
+		},
+		{
+			Host:       "ghe.internal",
+			APIURL:     "https://api.ghe.internal/",
+			GraphQLURL: "https://api.ghe.internal/graphql",
+			CodecovURL: "codecov.internal",
+		},
+	}, hosts)
+
+	t.Run("github.com", func(t *testing.T) {
+		_, err := ReadHosts(strings.NewReader(`[{"host": "github.com"}]`))
+		assert.Error(t, err)
+	})
+
+	t.Run("empty host", func(t *testing.T) {
+		_, err := ReadHosts(strings.NewReader(`[{"api_url": "https://github.example.com/api/v3/"}]`))
+		assert.Error(t, err)
+	})
+}
+
+func TestGetSummaryEnterprise(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/platform/tools", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stargazers_count": 3, "owner": {"login": "platform", "type": "User"}}`)
+	})
+	mux.HandleFunc("/api/v3/users/platform", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"login": "platform", "type": "User", "created_at": "2019-01-01T00:00:00Z"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	h := Host{Host: "github.example.com", APIURL: server.URL + "/api/v3/"}
+	client, err := h.NewClient(server.Client())
+	require.NoError(t, err)
+
+	summarizer := GitHubSummarizer{
+		Enterprise: map[string]*GitHubSummarizer{"github.example.com": {GitHubClient: client}},
+	}
+
+	s, err := summarizer.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "github.example.com", Path: "/platform/tools"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, *s.NumStartsRepo)
+
+	_, err = summarizer.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "other.example.com", Path: "/platform/tools"})
+	assert.Error(t, err)
+
+	// no enterprise fetcher, so responsiveness is skipped
+	assert.False(t, ResponsivenessFetcher{}.IsConfigured(url.URL{Host: "github.example.com"}))
+}
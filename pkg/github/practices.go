@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v35/github"
+)
+
+// Practices is how repository is maintained, as GitHub tells to anyone, for scorecard checks
+type Practices struct {
+	IsBranchProtected *bool // default branch, nil when not known
+	NumMergedPRs      *int  // latest merged pull requests, nil when not known since GraphQL needs token
+	NumReviewedPRs    int   // of them approved, or merged by someone other than author
+	NumReleases       int   // latest releases
+	NumSignedReleases int   // of them with signature or provenance assets
+}
+
+// signatureExtensions are release assets that sign other assets, same as in OpenSSF Scorecard
+var signatureExtensions = []string{".asc", ".minisig", ".sig", ".sign", ".sigstore", ".sigstore.json", ".intoto.jsonl"}
+
+const reviewsQuery = `query($owner: String!, $name: String!, $first: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(first: $first, states: MERGED, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      nodes {
+        author { login }
+        mergedBy { login }
+        reviews(states: APPROVED) { totalCount }
+      }
+    }
+  }
+}`
+
+type gqlReviews struct {
+	PullRequests struct {
+		Nodes []struct {
+			Author struct {
+				Login string `json:"login"`
+			} `json:"author"`
+			MergedBy struct {
+				Login string `json:"login"`
+			} `json:"mergedBy"`
+			Reviews struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"reviews"`
+		} `json:"nodes"`
+	} `json:"pullRequests"`
+}
+
+// GetPractices gets branch protection of branch, latest 5 releases, and latest 30 merged pull requests when there is GraphQL batcher.
+// When pull requests can not be fetched, practices without code review are returned along with error.
+func (c *GitHubSummarizer) GetPractices(ctx context.Context, ghURL url.URL, branch string) (*Practices, error) {
+	s, err := c.forHost(ghURL.Host)
+	if err != nil {
+		return nil, err
+	}
+	owner, repo := ParseGitHubURL(ghURL)
+	var p Practices
+
+	b, _, err := s.GitHubClient.Repositories.GetBranch(ctx, owner, repo, branch)
+	if err != nil {
+		return nil, fmt.Errorf("can not get github branch %s %s %s: %w", owner, repo, branch, err)
+	}
+	p.IsBranchProtected = b.Protected
+
+	releases, _, err := s.GitHubClient.Repositories.ListReleases(ctx, owner, repo, &github.ListOptions{PerPage: 5})
+	if err != nil {
+		return nil, fmt.Errorf("can not get github releases %s %s: %w", owner, repo, err)
+	}
+	for _, r := range releases {
+		p.NumReleases++
+		if isSigned(r.Assets) {
+			p.NumSignedReleases++
+		}
+	}
+
+	if s.Batcher == nil {
+		return &p, nil
+	}
+	resp, err := doGraphQL(ctx, s.Batcher.HTTPClient, s.Batcher.URL, reviewsQuery, map[string]interface{}{"owner": owner, "name": repo, "first": 30})
+	if err != nil {
+		return &p, fmt.Errorf("can not get github pull requests %s %s: %w", owner, repo, err)
+	}
+	if err := graphQLError(resp); err != nil {
+		return &p, fmt.Errorf("can not get github pull requests %s %s: %w", owner, repo, err)
+	}
+	var r gqlReviews
+	if err := json.Unmarshal(resp.Data["repository"], &r); err != nil {
+		return &p, fmt.Errorf("can not decode pull requests: %w", err)
+	}
+	merged := len(r.PullRequests.Nodes)
+	p.NumMergedPRs = &merged
+	for _, pr := range r.PullRequests.Nodes {
+		if pr.Reviews.TotalCount > 0 || (pr.MergedBy.Login != "" && pr.MergedBy.Login != pr.Author.Login) {
+			p.NumReviewedPRs++
+		}
+	}
+	return &p, nil
+}
+
+func isSigned(assets []*github.ReleaseAsset) bool {
+	for _, a := range assets {
+		for _, ext := range signatureExtensions {
+			if strings.HasSuffix(strings.ToLower(a.GetName()), ext) {
+				return true
+			}
+		}
+	}
+	return false
+}
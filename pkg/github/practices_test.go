@@ -0,0 +1,76 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v35/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPractices(t *testing.T) {
+	reviews, err := ioutil.ReadFile("testdata/graphql_reviews_response.json")
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/gin-gonic/gin/branches/master", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "master", "protected": true}`)
+	})
+	mux.HandleFunc("/repos/gin-gonic/gin/releases", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "5", r.URL.Query().Get("per_page"))
+		fmt.Fprint(w, `[
+			{"tag_name": "v1.7.4", "assets": [{"name": "gin_linux_amd64.tar.gz"}, {"name": "gin_linux_amd64.tar.gz.sig"}]},
+			{"tag_name": "v1.7.3", "assets": [{"name": "multiple.intoto.jsonl"}]},
+			{"tag_name": "v1.7.2", "assets": []}
+		]`)
+	})
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(reviews)
+	})
+	mux.HandleFunc("/graphql-failing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := github.NewClient(server.Client())
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	ghURL := url.URL{Scheme: "https", Host: "github.com", Path: "/gin-gonic/gin"}
+
+	t.Run("with token", func(t *testing.T) {
+		summarizer := GitHubSummarizer{GitHubClient: client, Batcher: &GraphQLBatcher{HTTPClient: server.Client(), URL: server.URL + "/graphql"}}
+		p, err := summarizer.GetPractices(context.Background(), ghURL, "master")
+		require.NoError(t, err)
+		assert.Equal(t, &Practices{
+			IsBranchProtected: github.Bool(true),
+			NumMergedPRs:      github.Int(3),
+			NumReviewedPRs:    2,
+			NumReleases:       3,
+			NumSignedReleases: 2,
+		}, p)
+	})
+
+	t.Run("without token code review is not known", func(t *testing.T) {
+		summarizer := GitHubSummarizer{GitHubClient: client}
+		p, err := summarizer.GetPractices(context.Background(), ghURL, "master")
+		require.NoError(t, err)
+		assert.Nil(t, p.NumMergedPRs)
+		assert.Equal(t, 3, p.NumReleases)
+	})
+
+	t.Run("pull requests can not be fetched", func(t *testing.T) {
+		summarizer := GitHubSummarizer{GitHubClient: client, Batcher: &GraphQLBatcher{HTTPClient: server.Client(), URL: server.URL + "/graphql-failing"}}
+		p, err := summarizer.GetPractices(context.Background(), ghURL, "master")
+		assert.Error(t, err)
+		require.NotNil(t, p)
+		assert.Nil(t, p.NumMergedPRs)
+		assert.Equal(t, github.Bool(true), p.IsBranchProtected)
+		assert.Equal(t, 2, p.NumSignedReleases)
+	})
+}
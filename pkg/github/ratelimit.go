@@ -0,0 +1,225 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v35/github"
+)
+
+// ErrRateLimited is returned when GitHub quota is exhausted and waiting for reset is too long
+var ErrRateLimited = errors.New("rate_limited")
+
+// ErrorKindEnum is kind of error of getting GitHub data, it is in collector result
+type ErrorKindEnum string
+
+const (
+	ErrorKindRateLimited ErrorKindEnum = "rate_limited"
+	ErrorKindNotFound    ErrorKindEnum = "not_found"
+	ErrorKindOther       ErrorKindEnum = "other"
+)
+
+// ErrorKind classifies error of getting GitHub data
+func ErrorKind(err error) ErrorKindEnum {
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	var respErr *github.ErrorResponse
+	switch {
+	case errors.Is(err, ErrRateLimited), errors.As(err, &rateErr), errors.As(err, &abuseErr):
+		return ErrorKindRateLimited
+	case errors.As(err, &respErr) && respErr.Response != nil && respErr.Response.StatusCode == http.StatusNotFound:
+		return ErrorKindNotFound
+	default:
+		return ErrorKindOther
+	}
+}
+
+type rateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// RateLimitTransport tracks GitHub quota from response headers.
+// When quota is exhausted it pauses until reset, and retries requests that hit secondary rate limits.
+// If wait is longer than MaxWait, requests fail with ErrRateLimited without calling GitHub.
+type RateLimitTransport struct {
+	Transport  http.RoundTripper // default is http.DefaultTransport
+	MaxWait    time.Duration     // max pause for single request
+	MaxRetries int               // max retries of request after secondary rate limit, default is 3
+
+	mu     sync.Mutex
+	limits map[string]rateLimit // REST and GraphQL have separate quotas
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// RoundTrip sends request, waiting for quota if needed
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	maxRetries := t.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+	resource := resourceOf(req)
+
+	for attempt := 0; ; attempt++ {
+		if err := t.Wait(req.Context(), resource); err != nil {
+			return nil, err
+		}
+
+		r := req
+		if attempt > 0 {
+			r = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, fmt.Errorf("can not get body for retry: %w", err)
+				}
+				r.Body = body
+			}
+		}
+
+		res, err := transport.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+		t.update(resource, res.Header)
+
+		wait, limited := t.retryAfter(res)
+		if !limited {
+			return res, nil
+		}
+		res.Body.Close()
+
+		canRetry := attempt < maxRetries && (req.Body == nil || req.GetBody != nil)
+		if !canRetry || wait > t.MaxWait {
+			return nil, fmt.Errorf("%w: %s %s got status %d, retry after %s", ErrRateLimited, req.Method, req.URL.Path, res.StatusCode, wait.Round(time.Second))
+		}
+		log.Printf("github: %s rate limit is hit, retrying in %s\n", resource, wait.Round(time.Second))
+		if err := t.doSleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Wait pauses until quota of resource resets, if it is exhausted.
+// Returns ErrRateLimited if it is longer than MaxWait.
+func (t *RateLimitTransport) Wait(ctx context.Context, resource string) error {
+	t.mu.Lock()
+	limit, ok := t.limits[resource]
+	t.mu.Unlock()
+	if !ok || limit.Remaining > 0 {
+		return nil
+	}
+
+	wait := limit.Reset.Sub(t.getNow())
+	if wait <= 0 {
+		return nil
+	}
+	wait += time.Second
+	if wait > t.MaxWait {
+		return fmt.Errorf("%w: %s quota is exhausted until %s", ErrRateLimited, resource, limit.Reset.Format(time.RFC3339))
+	}
+	log.Printf("github: %s quota is exhausted, waiting %s until reset\n", resource, wait.Round(time.Second))
+	if err := t.doSleep(ctx, wait); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	delete(t.limits, resource)
+	t.mu.Unlock()
+	return nil
+}
+
+// update reads quota from response headers and logs it every 100 requests and when it is low
+func (t *RateLimitTransport) update(resource string, h http.Header) {
+	remaining, errRemaining := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	reset, errReset := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if errRemaining != nil || errReset != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+
+	t.mu.Lock()
+	if t.limits == nil {
+		t.limits = map[string]rateLimit{}
+	}
+	t.limits[resource] = rateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+	t.mu.Unlock()
+
+	if remaining%100 == 0 || remaining < 10 {
+		log.Printf("github: %s quota remaining %d of %d, resets at %s\n", resource, remaining, limit, time.Unix(reset, 0).Format(time.RFC3339))
+	}
+}
+
+// retryAfter checks if response is primary or secondary rate limit and how long to wait before retry
+func (t *RateLimitTransport) retryAfter(res *http.Response) (time.Duration, bool) {
+	if res.StatusCode != http.StatusForbidden && res.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if s, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil {
+		return time.Duration(s) * time.Second, true
+	}
+
+	if res.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return time.Unix(reset, 0).Sub(t.getNow()) + time.Second, true
+		}
+	}
+
+	// secondary rate limit without Retry-After, GitHub recommends to wait at least a minute
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err == nil && strings.Contains(strings.ToLower(string(body)), "rate limit") {
+		return time.Minute, true
+	}
+	if res.StatusCode == http.StatusTooManyRequests {
+		return time.Minute, true
+	}
+	return 0, false
+}
+
+func (t *RateLimitTransport) getNow() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+func (t *RateLimitTransport) doSleep(ctx context.Context, d time.Duration) error {
+	if t.sleep != nil {
+		return t.sleep(ctx, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// resourceOf is GitHub quota that request uses
+func resourceOf(req *http.Request) string {
+	if strings.HasSuffix(req.URL.Path, "/graphql") {
+		return "graphql"
+	}
+	return "core"
+}
@@ -0,0 +1,115 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v35/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTransport(now time.Time, maxWait time.Duration) (*RateLimitTransport, *[]time.Duration) {
+	var slept []time.Duration
+	t := &RateLimitTransport{
+		MaxWait: maxWait,
+		now:     func() time.Time { return now },
+		sleep: func(ctx context.Context, d time.Duration) error {
+			slept = append(slept, d)
+			return nil
+		},
+	}
+	return t, &slept
+}
+
+func TestRateLimitTransportSecondaryLimit(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "You have exceeded a secondary rate limit."}`)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	transport, slept := newTestTransport(time.Now(), time.Hour)
+	client := &http.Client{Transport: transport}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/graphql", strings.NewReader(`{"query": "{}"}`))
+	require.NoError(t, err)
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []time.Duration{30 * time.Second}, *slept)
+}
+
+func TestRateLimitTransportExhaustedQuota(t *testing.T) {
+	now := time.Date(2021, 8, 1, 10, 0, 0, 0, time.UTC)
+	reset := now.Add(10 * time.Minute)
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	t.Run("wait is too long", func(t *testing.T) {
+		transport, slept := newTestTransport(now, time.Minute)
+		client := &http.Client{Transport: transport}
+
+		res, err := client.Get(server.URL + "/repos/a/b")
+		require.NoError(t, err)
+		res.Body.Close()
+
+		calls = 0
+		_, err = client.Get(server.URL + "/repos/a/c")
+		assert.True(t, errors.Is(err, ErrRateLimited))
+		assert.Equal(t, ErrorKindRateLimited, ErrorKind(err))
+		assert.Equal(t, 0, calls)
+		assert.Empty(t, *slept)
+
+		// quotas are separate
+		res, err = client.Post(server.URL+"/graphql", "application/json", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("waits until reset", func(t *testing.T) {
+		transport, slept := newTestTransport(now, time.Hour)
+		client := &http.Client{Transport: transport}
+
+		for i := 0; i < 2; i++ {
+			res, err := client.Get(server.URL + "/repos/a/b")
+			require.NoError(t, err)
+			res.Body.Close()
+		}
+		assert.Equal(t, []time.Duration{10*time.Minute + time.Second}, *slept)
+	})
+}
+
+func TestErrorKind(t *testing.T) {
+	notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	assert.Equal(t, ErrorKindNotFound, ErrorKind(fmt.Errorf("can not get: %w", notFound)))
+	assert.Equal(t, ErrorKindRateLimited, ErrorKind(&github.RateLimitError{}))
+	assert.Equal(t, ErrorKindRateLimited, ErrorKind(&github.AbuseRateLimitError{}))
+	assert.Equal(t, ErrorKindOther, ErrorKind(errors.New("some")))
+}
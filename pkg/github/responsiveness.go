@@ -0,0 +1,272 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Responsiveness is how maintainers answer issues and pull requests in recent window
+type Responsiveness struct {
+	WindowDays                    int      `json:"github_responsiveness_window_days"`
+	IssuesOpened                  int      `json:"github_issues_opened"`
+	IssuesClosed                  int      `json:"github_issues_closed"`
+	IssuesClosedOpenedRatio       *float64 `json:"github_issues_closed_opened_ratio,omitempty"`
+	IssueFirstResponseMedianHours *float64 `json:"github_issues_first_response_median_hours,omitempty"`
+	PRFirstResponseMedianHours    *float64 `json:"github_prs_first_response_median_hours,omitempty"`
+	IssuesAnswered                int      `json:"github_issues_answered"`   // of sampled issues created in window
+	IssuesUnanswered              int      `json:"github_issues_unanswered"` // of sampled issues created in window
+	IssueResponseRate             *float64 `json:"github_issues_response_rate,omitempty"`
+	PRsAnswered                   int      `json:"github_prs_answered"`   // of sampled pull requests created in window
+	PRsUnanswered                 int      `json:"github_prs_unanswered"` // of sampled pull requests created in window
+	PRResponseRate                *float64 `json:"github_prs_response_rate,omitempty"`
+	PRsClosed                     int      `json:"github_prs_closed"` // includes merged
+	PRsMerged                     int      `json:"github_prs_merged"`
+	PRMergeRate                   *float64 `json:"github_prs_merge_rate,omitempty"`
+	StaleOpenPRs                  int      `json:"github_prs_stale_open"`
+}
+
+// maintainerAssociations are authors that respond on behalf of repository
+var maintainerAssociations = map[string]bool{"OWNER": true, "MEMBER": true, "COLLABORATOR": true}
+
+const responsivenessCountsQuery = `query($opened: String!, $closed: String!, $prsClosed: String!, $prsMerged: String!, $prsStale: String!) {
+	opened: search(type: ISSUE, query: $opened, first: 0) { issueCount }
+	closed: search(type: ISSUE, query: $closed, first: 0) { issueCount }
+	prsClosed: search(type: ISSUE, query: $prsClosed, first: 0) { issueCount }
+	prsMerged: search(type: ISSUE, query: $prsMerged, first: 0) { issueCount }
+	prsStale: search(type: ISSUE, query: $prsStale, first: 0) { issueCount }
+}`
+
+const responsivenessItemsQuery = `query($query: String!, $after: String) {
+	search(type: ISSUE, query: $query, first: 50, after: $after) {
+		nodes {
+			__typename
+			... on Issue {
+				createdAt
+				author { login }
+				comments(first: 20) { nodes { ...response } }
+			}
+			... on PullRequest {
+				createdAt
+				author { login }
+				comments(first: 20) { nodes { ...response } }
+				reviews(first: 20) { nodes { ...response } }
+			}
+		}
+		pageInfo { hasNextPage endCursor }
+	}
+}
+
+fragment response on Comment {
+	createdAt
+	author { login }
+	authorAssociation
+}`
+
+type gqlAuthor struct {
+	Login string `json:"login"`
+}
+
+type gqlResponseNode struct {
+	CreatedAt         time.Time  `json:"createdAt"`
+	Author            *gqlAuthor `json:"author"`
+	AuthorAssociation string     `json:"authorAssociation"`
+}
+
+type gqlItem struct {
+	Typename  string     `json:"__typename"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Author    *gqlAuthor `json:"author"`
+	Comments  struct {
+		Nodes []gqlResponseNode `json:"nodes"`
+	} `json:"comments"`
+	Reviews struct {
+		Nodes []gqlResponseNode `json:"nodes"`
+	} `json:"reviews"`
+}
+
+// firstResponse is time of first comment or review by maintainer other than author
+func (item gqlItem) firstResponse() (time.Time, bool) {
+	var first time.Time
+	for _, n := range append(item.Comments.Nodes, item.Reviews.Nodes...) {
+		if !maintainerAssociations[n.AuthorAssociation] || n.Author == nil {
+			continue
+		}
+		if item.Author != nil && n.Author.Login == item.Author.Login {
+			continue
+		}
+		if first.IsZero() || n.CreatedAt.Before(first) {
+			first = n.CreatedAt
+		}
+	}
+	return first, !first.IsZero()
+}
+
+// ResponsivenessFetcher gets responsiveness with GitHub GraphQL search
+type ResponsivenessFetcher struct {
+	HTTPClient *http.Client // with authentication, GitHub GraphQL API does not work without token
+	URL        string       // e.g. https://api.github.com/graphql
+	Window     time.Duration
+	StaleAfter time.Duration                    // open pull request without updates for this long is stale
+	MaxItems   int                              // max number of issues and pull requests to get response times from
+	Enterprise map[string]ResponsivenessFetcher // optional, by GitHub Enterprise host
+	now        func() time.Time
+}
+
+// forHost returns fetcher of GitHub instance of repository
+func (c ResponsivenessFetcher) forHost(host string) (ResponsivenessFetcher, bool) {
+	if isPublic(host) {
+		return c, c.HTTPClient != nil
+	}
+	f, ok := c.Enterprise[strings.ToLower(host)]
+	return f, ok && f.HTTPClient != nil
+}
+
+// IsConfigured is true when responsiveness can be fetched for repository
+func (c ResponsivenessFetcher) IsConfigured(ghURL url.URL) bool {
+	_, ok := c.forHost(ghURL.Host)
+	return ok
+}
+
+// GetResponsiveness computes responsiveness of repository.
+// Response times are from issues and pull requests created in window, without ones that have no response yet.
+// Items without response are counted as unanswered, so median does not hide that most of them are ignored.
+func (c ResponsivenessFetcher) GetResponsiveness(ctx context.Context, ghURL url.URL) (*Responsiveness, error) {
+	c, ok := c.forHost(ghURL.Host)
+	if !ok {
+		return nil, errors.New("responsiveness is not configured")
+	}
+	owner, repo := ParseGitHubURL(ghURL)
+	if owner == "" {
+		return nil, fmt.Errorf("can not parse GitHub URL %s", ghURL.String())
+	}
+
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+	since := now.Add(-c.Window).Format("2006-01-02")
+	staleBefore := now.Add(-c.StaleAfter).Format("2006-01-02")
+	repoQualifier := fmt.Sprintf("repo:%s/%s", owner, repo)
+
+	r := Responsiveness{WindowDays: int(c.Window.Hours() / 24)}
+
+	resp, err := doGraphQL(ctx, c.HTTPClient, c.URL, responsivenessCountsQuery, map[string]interface{}{
+		"opened":    repoQualifier + " is:issue created:>=" + since,
+		"closed":    repoQualifier + " is:issue closed:>=" + since,
+		"prsClosed": repoQualifier + " is:pr closed:>=" + since,
+		"prsMerged": repoQualifier + " is:pr merged:>=" + since,
+		"prsStale":  repoQualifier + " is:pr is:open updated:<" + staleBefore,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not get issue counts: %w", err)
+	}
+	if err := graphQLError(resp); err != nil {
+		return nil, fmt.Errorf("can not get issue counts: %w", err)
+	}
+	counts := map[string]*int{"opened": &r.IssuesOpened, "closed": &r.IssuesClosed, "prsClosed": &r.PRsClosed, "prsMerged": &r.PRsMerged, "prsStale": &r.StaleOpenPRs}
+	for alias, v := range counts {
+		var search struct {
+			IssueCount int `json:"issueCount"`
+		}
+		if err := json.Unmarshal(resp.Data[alias], &search); err != nil {
+			return nil, fmt.Errorf("can not decode %s count: %w", alias, err)
+		}
+		*v = search.IssueCount
+	}
+	r.IssuesClosedOpenedRatio = ratio(r.IssuesClosed, r.IssuesOpened)
+	r.PRMergeRate = ratio(r.PRsMerged, r.PRsClosed)
+
+	var issueHours, prHours []float64
+	var after interface{}
+	for numItems := 0; c.MaxItems <= 0 || numItems < c.MaxItems; {
+		resp, err := doGraphQL(ctx, c.HTTPClient, c.URL, responsivenessItemsQuery, map[string]interface{}{
+			"query": repoQualifier + " created:>=" + since + " sort:created-desc",
+			"after": after,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("can not get issues: %w", err)
+		}
+		if err := graphQLError(resp); err != nil {
+			return nil, fmt.Errorf("can not get issues: %w", err)
+		}
+		var page struct {
+			Nodes    []gqlItem `json:"nodes"`
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		}
+		if err := json.Unmarshal(resp.Data["search"], &page); err != nil {
+			return nil, fmt.Errorf("can not decode issues: %w", err)
+		}
+
+		for _, item := range page.Nodes {
+			numItems++
+			first, ok := item.firstResponse()
+			isPR := item.Typename == "PullRequest"
+			switch {
+			case !ok && isPR:
+				r.PRsUnanswered++
+			case !ok:
+				r.IssuesUnanswered++
+			case isPR:
+				prHours = append(prHours, first.Sub(item.CreatedAt).Hours())
+			default:
+				issueHours = append(issueHours, first.Sub(item.CreatedAt).Hours())
+			}
+		}
+
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		after = page.PageInfo.EndCursor
+	}
+	r.IssueFirstResponseMedianHours = median(issueHours)
+	r.PRFirstResponseMedianHours = median(prHours)
+	r.IssuesAnswered, r.PRsAnswered = len(issueHours), len(prHours)
+	r.IssueResponseRate = ratio(r.IssuesAnswered, r.IssuesAnswered+r.IssuesUnanswered)
+	r.PRResponseRate = ratio(r.PRsAnswered, r.PRsAnswered+r.PRsUnanswered)
+
+	return &r, nil
+}
+
+func graphQLError(resp *gqlResponse) error {
+	if len(resp.Errors) == 0 {
+		return nil
+	}
+	if resp.Errors[0].Type == "RATE_LIMITED" {
+		return fmt.Errorf("%w: %s", ErrRateLimited, resp.Errors[0].Message)
+	}
+	return errors.New(resp.Errors[0].Message)
+}
+
+func ratio(a, b int) *float64 {
+	if b == 0 {
+		return nil
+	}
+	v := math.Round(float64(a)/float64(b)*100) / 100
+	return &v
+}
+
+func median(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	m := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		m = (sorted[len(sorted)/2-1] + m) / 2
+	}
+	m = math.Round(m*10) / 10
+	return &m
+}
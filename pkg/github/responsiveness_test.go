@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponsivenessFetcher(t *testing.T) {
+	responses := map[string][]byte{}
+	for _, name := range []string{"counts", "items_1", "items_2"} {
+		b, err := ioutil.ReadFile("testdata/graphql_responsiveness_" + name + ".json")
+		require.NoError(t, err)
+		responses[name] = b
+	}
+
+	var variables []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		variables = append(variables, req.Variables)
+		switch {
+		case strings.Contains(req.Query, "opened: search"):
+			w.Write(responses["counts"])
+		case req.Variables["after"] == nil:
+			w.Write(responses["items_1"])
+		default:
+			w.Write(responses["items_2"])
+		}
+	}))
+	defer server.Close()
+
+	fetcher := ResponsivenessFetcher{
+		HTTPClient: server.Client(),
+		URL:        server.URL,
+		Window:     90 * 24 * time.Hour,
+		StaleAfter: 30 * 24 * time.Hour,
+		now:        func() time.Time { return time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC) },
+	}
+	r, err := fetcher.GetResponsiveness(context.Background(), url.URL{Scheme: "https", Host: "github.com", Path: "/gin-gonic/gin"})
+	require.NoError(t, err)
+
+	require.Len(t, variables, 3)
+	assert.Equal(t, "repo:gin-gonic/gin is:issue created:>=2021-05-03", variables[0]["opened"])
+	assert.Equal(t, "repo:gin-gonic/gin is:pr is:open updated:<2021-07-02", variables[0]["prsStale"])
+	assert.Equal(t, "Y3Vyc29yOjM=", variables[2]["after"])
+
+	ptr := func(v float64) *float64 { return &v }
+	assert.Equal(t, &Responsiveness{
+		WindowDays:                    90,
+		IssuesOpened:                  40,
+		IssuesClosed:                  30,
+		IssuesClosedOpenedRatio:       ptr(0.75),
+		IssueFirstResponseMedianHours: ptr(6),
+		PRFirstResponseMedianHours:    ptr(24),
+		IssuesAnswered:                2,
+		IssuesUnanswered:              1,
+		IssueResponseRate:             ptr(0.67),
+		PRsAnswered:                   1,
+		PRResponseRate:                ptr(1),
+		PRsClosed:                     20,
+		PRsMerged:                     15,
+		PRMergeRate:                   ptr(0.75),
+		StaleOpenPRs:                  7,
+	}, r)
+}
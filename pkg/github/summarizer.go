@@ -6,19 +6,95 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v35/github"
+
+	"github.com/nikolaydubina/import-graph/pkg/hosting"
 )
 
 // GitHubSummarizer collects summary about github repo
 type GitHubSummarizer struct {
 	GitHubClient *github.Client
+	Batcher      *GraphQLBatcher              // optional, prefetched summaries are used before REST
+	RateLimiter  *RateLimitTransport          // optional, transport of GitHubClient
+	Enterprise   map[string]*GitHubSummarizer // optional, by GitHub Enterprise host
+
+	owners    map[string]*OwnerSummary  // login to owner
+	summaries map[string]*GitHubSummary // owner/repo to summary
+}
+
+// forHost returns summarizer of GitHub instance of repository
+func (c *GitHubSummarizer) forHost(host string) (*GitHubSummarizer, error) {
+	if isPublic(host) {
+		return c, nil
+	}
+	if s, ok := c.Enterprise[strings.ToLower(host)]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("GitHub Enterprise host %s is not configured", host)
+}
+
+// Prefetch fetches summaries of many repositories at once, if batcher is set
+func (c *GitHubSummarizer) Prefetch(ctx context.Context, ghURLs []url.URL) error {
+	byHost := map[string][]url.URL{}
+	for _, u := range ghURLs {
+		byHost[strings.ToLower(u.Host)] = append(byHost[strings.ToLower(u.Host)], u)
+	}
+	var errs []string
+	for host, urls := range byHost {
+		s, err := c.forHost(host)
+		if err != nil || s.Batcher == nil {
+			continue
+		}
+		if err := s.Batcher.Prefetch(ctx, Repos(urls)); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
 }
 
-// GetSummary collects summary about github repo
+// GetSummary collects summary about github repo, once per repo
 func (c *GitHubSummarizer) GetSummary(ctx context.Context, ghURL url.URL) (*GitHubSummary, error) {
+	s, err := c.forHost(ghURL.Host)
+	if err != nil {
+		return nil, err
+	}
+	if s.summaries == nil {
+		s.summaries = map[string]*GitHubSummary{}
+	}
 	owner, repo := ParseGitHubURL(ghURL)
+	key := strings.ToLower(owner + "/" + repo)
+	if summary, ok := s.summaries[key]; ok {
+		return summary, nil
+	}
+	summary, err := s.getSummary(ctx, ghURL)
+	if err != nil {
+		return nil, err
+	}
+	s.summaries[key] = summary
+	return summary, nil
+}
+
+func (c *GitHubSummarizer) getSummary(ctx context.Context, ghURL url.URL) (*GitHubSummary, error) {
+	owner, repo := ParseGitHubURL(ghURL)
+	if c.Batcher != nil {
+		if s, ok := c.Batcher.Get(owner, repo); ok {
+			s.OwnerSummary.setAge(time.Now())
+			return s, nil
+		}
+	}
 	ghRepo, _, err := c.GitHubClient.Repositories.Get(ctx, owner, repo)
+	// client refuses requests without calling GitHub until quota resets, wait for it same as for other requests
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) && c.RateLimiter != nil {
+		if err = c.RateLimiter.Wait(ctx, "core"); err == nil {
+			ghRepo, _, err = c.GitHubClient.Repositories.Get(ctx, owner, repo)
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("can not get github repo %s %s: %w", owner, repo, err)
 	}
@@ -26,16 +102,147 @@ func (c *GitHubSummarizer) GetSummary(ctx context.Context, ghURL url.URL) (*GitH
 		return nil, errors.New("retrieved repository info is nil")
 	}
 
-	summary := GitHubSummary{
-		NumStartsRepo: ghRepo.StargazersCount,
+	summary := NewGitHubSummary(ghRepo)
+	// owner data is not critical, summary is still useful without it
+	if o, err := c.getOwner(ctx, ghRepo.GetOwner()); err == nil {
+		summary.OwnerSummary = o
+		summary.OwnerSummary.setAge(time.Now())
+	}
+	return summary, nil
+}
+
+// getOwner fetches owner of repository, once per owner
+func (c *GitHubSummarizer) getOwner(ctx context.Context, owner *github.User) (*OwnerSummary, error) {
+	if c.owners == nil {
+		c.owners = map[string]*OwnerSummary{}
+	}
+	login := owner.GetLogin()
+	if o, ok := c.owners[login]; ok {
+		return o.copy(), nil
 	}
 
-	return &summary, nil
+	var o OwnerSummary
+	if owner.GetType() == "Organization" {
+		org, _, err := c.GitHubClient.Organizations.Get(ctx, login)
+		if err != nil {
+			return nil, fmt.Errorf("can not get github organization %s: %w", login, err)
+		}
+		o.IsVerified = org.GetIsVerified()
+		o.Requires2FA = org.TwoFactorRequirementEnabled
+		if org.CreatedAt != nil {
+			t := org.CreatedAt.UTC()
+			o.CreatedAt = &t
+		}
+
+		members, resp, err := c.GitHubClient.Organizations.ListMembers(ctx, login, &github.ListMembersOptions{PublicOnly: true, ListOptions: github.ListOptions{PerPage: 1}})
+		if err == nil {
+			n := len(members)
+			if resp.LastPage > 0 {
+				n = resp.LastPage
+			}
+			o.NumMembers = &n
+		}
+	} else {
+		user, _, err := c.GitHubClient.Users.Get(ctx, login)
+		if err != nil {
+			return nil, fmt.Errorf("can not get github user %s: %w", login, err)
+		}
+		if user.CreatedAt != nil {
+			t := user.CreatedAt.UTC()
+			o.CreatedAt = &t
+		}
+	}
+
+	c.owners[login] = &o
+	return o.copy(), nil
 }
 
 // GitHubSummary is used in collector result
 type GitHubSummary struct {
-	NumStartsRepo *int `json:"github_repo_stars,omitempty"`
+	NumStartsRepo *int       `json:"github_repo_stars,omitempty"`
+	NumForks      *int       `json:"github_repo_forks,omitempty"`
+	NumWatchers   *int       `json:"github_repo_watchers,omitempty"`
+	NumOpenIssues *int       `json:"github_repo_open_issues,omitempty"` // includes pull requests
+	IsArchived    bool       `json:"github_repo_archived"`
+	IsDisabled    bool       `json:"github_repo_disabled"`
+	IsFork        bool       `json:"github_repo_fork"`
+	Parent        string     `json:"github_repo_parent,omitempty"` // full name of repo this is fork of, e.g. gin-gonic/gin
+	DefaultBranch string     `json:"github_repo_default_branch,omitempty"`
+	License       string     `json:"github_repo_license,omitempty"` // SPDX identifier
+	Topics        []string   `json:"github_repo_topics,omitempty"`
+	CreatedAt     *time.Time `json:"github_repo_created_at,omitempty"`
+	PushedAt      *time.Time `json:"github_repo_pushed_at,omitempty"`
+	Homepage      string     `json:"github_repo_homepage,omitempty"`
+	IsOwnerOrg    bool       `json:"github_owner_is_org"`
+	*OwnerSummary `json:",omitempty"`
+}
+
+// OwnerSummary is who owns repository, to spot new or single person accounts
+type OwnerSummary struct {
+	IsVerified   bool       `json:"github_owner_verified"`               // organization verified its domain
+	Requires2FA  *bool      `json:"github_owner_requires_2fa,omitempty"` // visible only to organization admins
+	NumMembers   *int       `json:"github_owner_members,omitempty"`      // visible members of organization
+	HasSponsors  *bool      `json:"github_owner_sponsors,omitempty"`     // known only from GraphQL
+	CreatedAt    *time.Time `json:"github_owner_created_at,omitempty"`
+	OwnerAgeDays *uint      `json:"github_owner_age_days,omitempty"` // age of account that owns repository, not of people who commit to it
+}
+
+func (o *OwnerSummary) setAge(now time.Time) {
+	if o == nil || o.CreatedAt == nil {
+		return
+	}
+	days := uint(now.Sub(*o.CreatedAt).Hours() / 24)
+	o.OwnerAgeDays = &days
+}
+
+func (o *OwnerSummary) copy() *OwnerSummary {
+	c := *o
+	return &c
+}
+
+// HostingProvider is GitHub as hosting, summaries are shared with summarizer so repository is fetched once
+type HostingProvider struct {
+	Summarizer *GitHubSummarizer
+}
+
+// GetSummary gets fields of repository that all hostings have
+func (p HostingProvider) GetSummary(ctx context.Context, ghURL url.URL) (*hosting.Summary, error) {
+	s, err := p.Summarizer.GetSummary(ctx, ghURL)
+	if err != nil {
+		return nil, err
+	}
+	return s.Hosting(ghURL, time.Now()), nil
+}
+
+// NewGitHubSummary keeps fields of repository that are used in collector result
+func NewGitHubSummary(r *github.Repository) *GitHubSummary {
+	summary := GitHubSummary{
+		NumStartsRepo: r.StargazersCount,
+		NumForks:      r.ForksCount,
+		NumWatchers:   r.SubscribersCount,
+		NumOpenIssues: r.OpenIssuesCount,
+		IsArchived:    r.GetArchived(),
+		IsDisabled:    r.GetDisabled(),
+		IsFork:        r.GetFork(),
+		Parent:        r.GetParent().GetFullName(),
+		DefaultBranch: r.GetDefaultBranch(),
+		Topics:        r.Topics,
+		Homepage:      r.GetHomepage(),
+		IsOwnerOrg:    r.GetOwner().GetType() == "Organization",
+	}
+	// GitHub uses NOASSERTION when license is not recognized
+	if id := r.GetLicense().GetSPDXID(); id != "NOASSERTION" {
+		summary.License = id
+	}
+	if r.CreatedAt != nil {
+		t := r.CreatedAt.UTC()
+		summary.CreatedAt = &t
+	}
+	if r.PushedAt != nil {
+		t := r.PushedAt.UTC()
+		summary.PushedAt = &t
+	}
+	return &summary
 }
 
 func ParseGitHubURL(repoURL url.URL) (owner, repoName string) {
@@ -51,3 +258,18 @@ func ParseGitHubURL(repoURL url.URL) (owner, repoName string) {
 	}
 	return parts[0], parts[1]
 }
+
+// Hosting returns fields that all hostings have, open issues include pull requests
+func (s *GitHubSummary) Hosting(ghURL url.URL, now time.Time) *hosting.Summary {
+	h := hosting.Summary{
+		Provider:      hosting.ProviderGitHub,
+		URL:           ghURL.String(),
+		NumStars:      s.NumStartsRepo,
+		NumForks:      s.NumForks,
+		NumOpenIssues: s.NumOpenIssues,
+		IsArchived:    s.IsArchived,
+		License:       s.License,
+	}
+	h.SetLastPush(s.PushedAt, now)
+	return &h
+}
@@ -0,0 +1,128 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v35/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/hosting"
+)
+
+func TestNewGitHubSummary(t *testing.T) {
+	created := time.Date(2014, 6, 16, 23, 57, 25, 0, time.UTC)
+	pushed := time.Date(2021, 8, 1, 10, 0, 0, 0, time.UTC)
+
+	r := &github.Repository{
+		StargazersCount:  github.Int(100),
+		ForksCount:       github.Int(10),
+		SubscribersCount: github.Int(5),
+		OpenIssuesCount:  github.Int(3),
+		Archived:         github.Bool(true),
+		Fork:             github.Bool(true),
+		Parent:           &github.Repository{FullName: github.String("gin-gonic/gin")},
+		DefaultBranch:    github.String("master"),
+		License:          &github.License{SPDXID: github.String("MIT")},
+		Topics:           []string{"go", "http"},
+		CreatedAt:        &github.Timestamp{Time: created},
+		PushedAt:         &github.Timestamp{Time: pushed},
+		Homepage:         github.String("https://gin-gonic.com"),
+		Owner:            &github.User{Type: github.String("Organization")},
+	}
+
+	assert.Equal(t, &GitHubSummary{
+		NumStartsRepo: github.Int(100),
+		NumForks:      github.Int(10),
+		NumWatchers:   github.Int(5),
+		NumOpenIssues: github.Int(3),
+		IsArchived:    true,
+		IsFork:        true,
+		Parent:        "gin-gonic/gin",
+		DefaultBranch: "master",
+		License:       "MIT",
+		Topics:        []string{"go", "http"},
+		CreatedAt:     &created,
+		PushedAt:      &pushed,
+		Homepage:      "https://gin-gonic.com",
+		IsOwnerOrg:    true,
+	}, NewGitHubSummary(r))
+}
+
+func TestNewGitHubSummaryUnknownLicense(t *testing.T) {
+	r := &github.Repository{
+		License: &github.License{SPDXID: github.String("NOASSERTION")},
+		Owner:   &github.User{Type: github.String("User")},
+	}
+	s := NewGitHubSummary(r)
+	assert.Equal(t, "", s.License)
+	assert.False(t, s.IsOwnerOrg)
+}
+
+func TestParseGitHubURL(t *testing.T) {
+	owner, repo := ParseGitHubURL(url.URL{Scheme: "https", Host: "github.com", Path: "/gin-gonic/gin"})
+	assert.Equal(t, "gin-gonic", owner)
+	assert.Equal(t, "gin", repo)
+}
+
+func TestGetSummaryOwner(t *testing.T) {
+	var numOrgCalls, numRepoCalls int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/gin-gonic/gin", func(w http.ResponseWriter, r *http.Request) {
+		numRepoCalls++
+		fmt.Fprint(w, `{"stargazers_count": 100, "owner": {"login": "gin-gonic", "type": "Organization"}}`)
+	})
+	mux.HandleFunc("/repos/someone/tool", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stargazers_count": 1, "owner": {"login": "someone", "type": "User"}}`)
+	})
+	mux.HandleFunc("/orgs/gin-gonic", func(w http.ResponseWriter, r *http.Request) {
+		numOrgCalls++
+		fmt.Fprint(w, `{"login": "gin-gonic", "is_verified": true, "two_factor_requirement_enabled": true, "created_at": "2014-06-16T23:00:00Z"}`)
+	})
+	mux.HandleFunc("/orgs/gin-gonic/public_members", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/orgs/gin-gonic/public_members?per_page=1&page=7>; rel="last"`, r.Host))
+		fmt.Fprint(w, `[{"login": "a"}]`)
+	})
+	mux.HandleFunc("/users/someone", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"login": "someone", "type": "User", "created_at": "2021-07-01T00:00:00Z"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := github.NewClient(server.Client())
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	summarizer := GitHubSummarizer{GitHubClient: client}
+
+	for i := 0; i < 2; i++ {
+		s, err := summarizer.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "github.com", Path: "/gin-gonic/gin"})
+		require.NoError(t, err)
+		require.NotNil(t, s.OwnerSummary)
+		assert.True(t, s.IsOwnerOrg)
+		assert.True(t, s.IsVerified)
+		assert.Equal(t, github.Bool(true), s.Requires2FA)
+		assert.Equal(t, github.Int(7), s.NumMembers)
+		assert.Nil(t, s.HasSponsors)
+		assert.NotNil(t, s.OwnerAgeDays)
+	}
+	assert.Equal(t, 1, numOrgCalls)
+	assert.Equal(t, 1, numRepoCalls)
+
+	h, err := HostingProvider{Summarizer: &summarizer}.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "github.com", Path: "/gin-gonic/gin"})
+	require.NoError(t, err)
+	assert.Equal(t, hosting.ProviderGitHub, h.Provider)
+	assert.Equal(t, github.Int(100), h.NumStars)
+	assert.Equal(t, 1, numRepoCalls)
+
+	s, err := summarizer.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "github.com", Path: "/someone/tool"})
+	require.NoError(t, err)
+	assert.False(t, s.IsOwnerOrg)
+	assert.False(t, s.IsVerified)
+	assert.Nil(t, s.NumMembers)
+	assert.Equal(t, time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC), *s.OwnerSummary.CreatedAt)
+}
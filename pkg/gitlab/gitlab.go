@@ -0,0 +1,240 @@
+// Package gitlab collects repository stats from GitLab REST API, of gitlab.com or self-managed GitLab
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nikolaydubina/import-graph/pkg/hosting"
+	"github.com/nikolaydubina/import-graph/pkg/license"
+)
+
+// PublicHost is host of gitlab.com
+const PublicHost = "gitlab.com"
+
+// Host is self-managed GitLab instance
+type Host struct {
+	Host     string `json:"host"`                // as in module names and git URLs, e.g. gitlab.example.com
+	APIURL   string `json:"api_url,omitempty"`   // default is https://<host>/api/v4
+	TokenEnv string `json:"token_env,omitempty"` // environment variable with token, tokens are not kept in config
+}
+
+// ReadHosts reads JSON array of hosts and fills in default API URLs
+func ReadHosts(r io.Reader) ([]Host, error) {
+	var hosts []Host
+	if err := json.NewDecoder(r).Decode(&hosts); err != nil {
+		return nil, fmt.Errorf("can not decode hosts: %w", err)
+	}
+	for i, h := range hosts {
+		h.Host = strings.ToLower(strings.TrimSpace(h.Host))
+		if h.Host == "" {
+			return nil, errors.New("host is empty")
+		}
+		if h.Host == PublicHost {
+			return nil, errors.New("gitlab.com is configured by default")
+		}
+		if h.APIURL == "" {
+			h.APIURL = "https://" + h.Host + "/api/v4"
+		}
+		hosts[i] = h
+	}
+	return hosts, nil
+}
+
+// Client is client of single GitLab instance
+type Client struct {
+	HTTPClient *http.Client
+	APIURL     string // e.g. https://gitlab.com/api/v4
+	Token      string // optional, private projects need it
+}
+
+// GitLabSummary is used in collector result
+type GitLabSummary struct {
+	NumStars             *int       `json:"gitlab_repo_stars,omitempty"`
+	NumForks             *int       `json:"gitlab_repo_forks,omitempty"`
+	NumOpenIssues        *int       `json:"gitlab_repo_open_issues,omitempty"` // missing when issues are disabled
+	NumOpenMergeRequests *int       `json:"gitlab_repo_open_merge_requests,omitempty"`
+	IsArchived           bool       `json:"gitlab_repo_archived"`
+	Parent               string     `json:"gitlab_repo_parent,omitempty"` // path of project this is fork of, e.g. gitlab-org/gitlab
+	DefaultBranch        string     `json:"gitlab_repo_default_branch,omitempty"`
+	License              string     `json:"gitlab_repo_license,omitempty"` // SPDX identifier
+	Topics               []string   `json:"gitlab_repo_topics,omitempty"`
+	CreatedAt            *time.Time `json:"gitlab_repo_created_at,omitempty"`
+	LastActivityAt       *time.Time `json:"gitlab_repo_last_activity_at,omitempty"`
+	LastActivityDays     *uint      `json:"gitlab_repo_last_activity_days_since,omitempty"`
+}
+
+// project is part of GitLab project response that is used
+type project struct {
+	ID                int        `json:"id"`
+	StarCount         *int       `json:"star_count"`
+	ForksCount        *int       `json:"forks_count"`
+	OpenIssuesCount   *int       `json:"open_issues_count"`
+	Archived          bool       `json:"archived"`
+	DefaultBranch     string     `json:"default_branch"`
+	Topics            []string   `json:"topics"`
+	TagList           []string   `json:"tag_list"` // topics in GitLab before 14.0
+	CreatedAt         *time.Time `json:"created_at"`
+	LastActivityAt    *time.Time `json:"last_activity_at"`
+	ForkedFromProject *struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"forked_from_project"`
+	License *struct {
+		Key string `json:"key"`
+	} `json:"license"`
+}
+
+// GetSummary collects summary about project
+func (c Client) GetSummary(ctx context.Context, glURL url.URL) (*GitLabSummary, error) {
+	path := ParseGitLabURL(glURL)
+	if path == "" {
+		return nil, fmt.Errorf("can not parse GitLab URL %s", glURL.String())
+	}
+
+	var p project
+	if _, err := c.get(ctx, "/projects/"+url.PathEscape(path)+"?license=true", &p); err != nil {
+		return nil, fmt.Errorf("can not get gitlab project %s: %w", path, err)
+	}
+
+	s := GitLabSummary{
+		NumStars:       p.StarCount,
+		NumForks:       p.ForksCount,
+		NumOpenIssues:  p.OpenIssuesCount,
+		IsArchived:     p.Archived,
+		DefaultBranch:  p.DefaultBranch,
+		Topics:         p.Topics,
+		CreatedAt:      utc(p.CreatedAt),
+		LastActivityAt: utc(p.LastActivityAt),
+	}
+	if len(s.Topics) == 0 {
+		s.Topics = p.TagList
+	}
+	if p.ForkedFromProject != nil {
+		s.Parent = p.ForkedFromProject.PathWithNamespace
+	}
+	if p.License != nil {
+		s.License = license.KnownID(p.License.Key)
+	}
+	if s.LastActivityAt != nil {
+		days := uint(time.Since(*s.LastActivityAt).Hours() / 24)
+		s.LastActivityDays = &days
+	}
+
+	// merge requests are not critical, summary is still useful without them
+	resp, err := c.get(ctx, fmt.Sprintf("/projects/%d/merge_requests?state=opened&per_page=1", p.ID), nil)
+	if err == nil {
+		// GitLab does not count beyond 10000 items and omits total then
+		if n, err := strconv.Atoi(resp.Header.Get("X-Total")); err == nil {
+			s.NumOpenMergeRequests = &n
+		}
+	}
+
+	return &s, nil
+}
+
+// get makes GET request to API and decodes JSON response into v, if it is not nil
+func (c Client) get(ctx context.Context, path string, v interface{}) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.APIURL, "/")+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can not make request: %w", err)
+	}
+	if c.Token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.Token)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("can not make request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return nil, fmt.Errorf("can not decode response: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// Summarizer picks client by host of project URL
+type Summarizer struct {
+	Clients map[string]Client // by host, e.g. gitlab.com
+
+	summaries map[string]*GitLabSummary // host and path to summary
+}
+
+// GetSummary collects summary about project with client of its host, once per project
+func (c *Summarizer) GetSummary(ctx context.Context, glURL url.URL) (*GitLabSummary, error) {
+	client, ok := c.Clients[strings.ToLower(glURL.Host)]
+	if !ok {
+		return nil, fmt.Errorf("GitLab host %s is not configured", glURL.Host)
+	}
+	if c.summaries == nil {
+		c.summaries = map[string]*GitLabSummary{}
+	}
+	key := strings.ToLower(glURL.Host + "/" + ParseGitLabURL(glURL))
+	if s, ok := c.summaries[key]; ok {
+		return s, nil
+	}
+	s, err := client.GetSummary(ctx, glURL)
+	if err != nil {
+		return nil, err
+	}
+	c.summaries[key] = s
+	return s, nil
+}
+
+// HostingProvider is GitLab as hosting, summaries are shared with summarizer so project is fetched once
+type HostingProvider struct {
+	Summarizer *Summarizer
+}
+
+// GetSummary gets fields of project that all hostings have
+func (p HostingProvider) GetSummary(ctx context.Context, glURL url.URL) (*hosting.Summary, error) {
+	s, err := p.Summarizer.GetSummary(ctx, glURL)
+	if err != nil {
+		return nil, err
+	}
+	return s.Hosting(glURL, time.Now()), nil
+}
+
+// ParseGitLabURL returns full path of project, with all nested groups, e.g. gitlab-org/ci-cd/codequality
+func ParseGitLabURL(repoURL url.URL) string {
+	path := strings.Trim(strings.TrimSuffix(repoURL.Path, ".git"), "/")
+	if strings.Count(path, "/") < 1 {
+		return ""
+	}
+	return path
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// Hosting returns fields that all hostings have, last activity is taken as last push
+func (s *GitLabSummary) Hosting(glURL url.URL, now time.Time) *hosting.Summary {
+	h := hosting.Summary{
+		Provider:      hosting.ProviderGitLab,
+		URL:           glURL.String(),
+		NumStars:      s.NumStars,
+		NumForks:      s.NumForks,
+		NumOpenIssues: s.NumOpenIssues,
+		IsArchived:    s.IsArchived,
+		License:       s.License,
+	}
+	h.SetLastPush(s.LastActivityAt, now)
+	return &h
+}
@@ -0,0 +1,98 @@
+package gitlab
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/hosting"
+)
+
+func TestGetSummary(t *testing.T) {
+	project, err := ioutil.ReadFile("testdata/project_response.json")
+	require.NoError(t, err)
+
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("PRIVATE-TOKEN"))
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/gitlab-org%2Fci-cd%2Fcodequality":
+			assert.Equal(t, "true", r.URL.Query().Get("license"))
+			w.Write(project)
+		case "/api/v4/projects/4456656/merge_requests":
+			assert.Equal(t, "opened", r.URL.Query().Get("state"))
+			w.Header().Set("X-Total", "4")
+			w.Write([]byte(`[{"iid": 1}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	summarizer := Summarizer{Clients: map[string]Client{
+		"gitlab.com": {HTTPClient: server.Client(), APIURL: server.URL + "/api/v4", Token: "token"},
+	}}
+
+	s, err := summarizer.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "gitlab.com", Path: "/gitlab-org/ci-cd/codequality"})
+	require.NoError(t, err)
+
+	created := time.Date(2017, 10, 10, 12, 44, 0, 0, time.UTC)
+	activity := time.Date(2021, 9, 1, 8, 30, 0, 0, time.UTC)
+	require.NotNil(t, s.LastActivityDays)
+	s.LastActivityDays = nil
+	stars, forks, issues, mrs := 23, 51, 12, 4
+	assert.Equal(t, &GitLabSummary{
+		NumStars:             &stars,
+		NumForks:             &forks,
+		NumOpenIssues:        &issues,
+		NumOpenMergeRequests: &mrs,
+		Parent:               "gitlab-org/codequality",
+		DefaultBranch:        "master",
+		License:              "MIT",
+		Topics:               []string{"code quality", "ci"},
+		CreatedAt:            &created,
+		LastActivityAt:       &activity,
+	}, s)
+	assert.Equal(t, []string{"token", "token"}, tokens)
+
+	t.Run("hosting is from fetched summary", func(t *testing.T) {
+		h, err := HostingProvider{Summarizer: &summarizer}.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "gitlab.com", Path: "/gitlab-org/ci-cd/codequality"})
+		require.NoError(t, err)
+		assert.Equal(t, hosting.ProviderGitLab, h.Provider)
+		assert.Equal(t, &stars, h.NumStars)
+		assert.Equal(t, "MIT", h.License)
+		assert.Len(t, tokens, 2)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := summarizer.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "gitlab.com", Path: "/someone/missing"})
+		assert.Error(t, err)
+	})
+
+	t.Run("host is not configured", func(t *testing.T) {
+		_, err := summarizer.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "gitlab.example.com", Path: "/a/b"})
+		assert.Error(t, err)
+	})
+}
+
+func TestReadHosts(t *testing.T) {
+	hosts, err := ReadHosts(strings.NewReader(`[{"host": "GitLab.Example.com", "token_env": "GL_TOKEN"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []Host{{Host: "gitlab.example.com", APIURL: "https://gitlab.example.com/api/v4", TokenEnv: "GL_TOKEN"}}, hosts)
+
+	_, err = ReadHosts(strings.NewReader(`[{"host": "gitlab.com"}]`))
+	assert.Error(t, err)
+}
+
+func TestParseGitLabURL(t *testing.T) {
+	assert.Equal(t, "gitlab-org/ci-cd/codequality", ParseGitLabURL(url.URL{Host: "gitlab.com", Path: "/gitlab-org/ci-cd/codequality.git"}))
+	assert.Equal(t, "", ParseGitLabURL(url.URL{Host: "gitlab.com", Path: "/gitlab-org"}))
+}
@@ -8,6 +8,10 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // GitCmdLocalClient works with local git through os commands
@@ -58,6 +62,37 @@ func (g *GitCmdLocalClient) GetGitLog(gitURL url.URL) (GitLog, error) {
 	return gitlogs, nil
 }
 
+var releaseTag = regexp.MustCompile(`(^|/)v[0-9]+\.[0-9]+\.[0-9]+`)
+
+// GetReleaseDates fetches dates of semver tags, latest first
+func (g *GitCmdLocalClient) GetReleaseDates(gitURL url.URL) ([]time.Time, error) {
+	out, err := exec.Command(
+		"git",
+		fmt.Sprintf("--git-dir=%s/.git", g.DirPath(gitURL)),
+		"for-each-ref",
+		"--sort=-creatordate",
+		"--format=%(creatordate:unix) %(refname:short)",
+		"refs/tags",
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("can not list tags: %w", err)
+	}
+
+	var dates []time.Time
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		vals := strings.SplitN(strings.TrimSpace(line), " ", 2)
+		if len(vals) != 2 || !releaseTag.MatchString(vals[1]) {
+			continue
+		}
+		createdAt, err := strconv.ParseInt(vals[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad UNIX timestamp format for string(%s): %w", vals[0], err)
+		}
+		dates = append(dates, time.Unix(createdAt, 0))
+	}
+	return dates, nil
+}
+
 // DirPath gets path where git repo is stored locally
 func (g *GitCmdLocalClient) DirPath(gitURL url.URL) string {
 	return path.Join(g.Path, dirName(gitURL))
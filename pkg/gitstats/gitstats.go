@@ -11,9 +11,14 @@ type gitLogFetcher interface {
 	GetGitLog(gitURL url.URL) (GitLog, error)
 }
 
+type gitReleaseFetcher interface {
+	GetReleaseDates(gitURL url.URL) ([]time.Time, error)
+}
+
 // GitStatsFetcher computes git stats after fetching using provided storage
 type GitStatsFetcher struct {
-	GitLogFetcher gitLogFetcher
+	GitLogFetcher     gitLogFetcher
+	GitReleaseFetcher gitReleaseFetcher // optional
 }
 
 // GitStats contains information about single git repository computed using local git only
@@ -21,6 +26,9 @@ type GitStats struct {
 	LastCommit          time.Time `json:"last_commit,omitempty"`
 	DaysSinceLastCommit float64   `json:"last_commit_days_since"`
 	NumContributors     uint      `json:"num_contributors"`
+
+	LastRelease         *time.Time `json:"last_release,omitempty"` // nil if no releases
+	NumReleasesLastYear uint       `json:"num_releases_last_year"`
 }
 
 func (g *GitStatsFetcher) GetGitStats(gitURL url.URL) (*GitStats, error) {
@@ -37,5 +45,16 @@ func (g *GitStatsFetcher) GetGitStats(gitURL url.URL) (*GitStats, error) {
 		DaysSinceLastCommit: logs.DaysSinceLastCommit(),
 		NumContributors:     logs.NumContributors(),
 	}
+
+	if g.GitReleaseFetcher != nil {
+		if dates, err := g.GitReleaseFetcher.GetReleaseDates(gitURL); err == nil && len(dates) > 0 {
+			stats.LastRelease = &dates[0]
+			for _, d := range dates {
+				if time.Since(d) <= 365*24*time.Hour {
+					stats.NumReleasesLastYear++
+				}
+			}
+		}
+	}
 	return &stats, nil
 }
@@ -44,6 +44,22 @@ func (f *FileScanner) HasBenchmarks(path string) bool {
 	return found
 }
 
+// HasFuzzTests checks if repo at path has native Go fuzz tests
+func (f *FileScanner) HasFuzzTests(path string) bool {
+	found := false
+	filepath.Walk(path, func(path string, f os.FileInfo, err error) error {
+		if !strings.HasSuffix(f.Name(), "_test.go") {
+			return nil
+		}
+		if has, _ := fileHasString(path, "func Fuzz"); has {
+			found = has
+			return errors.New("found fuzz test")
+		}
+		return nil
+	})
+	return found
+}
+
 func fileHasString(path string, target string) (bool, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -0,0 +1,27 @@
+// Package golden compares output in tests with expected files in testdata.
+// Run tests with -update to rewrite expected files.
+package golden
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// Equal checks that output is same as content of file in testdata
+func Equal(t *testing.T, name string, output []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, ioutil.WriteFile(path, output, 0644))
+	}
+	expected, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(output))
+}
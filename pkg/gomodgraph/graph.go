@@ -0,0 +1,203 @@
+package gomodgraph
+
+import (
+	"sort"
+	"strings"
+)
+
+// Root returns main module of graph.
+// This is first module without incoming edges, which for `go mod graph` is first module in output.
+func (g Graph) Root() string {
+	if roots := g.Roots(); len(roots) > 0 {
+		return roots[0]
+	}
+	if len(g.Modules) > 0 {
+		return g.Modules[0].ModuleName
+	}
+	return ""
+}
+
+// Roots returns all modules without incoming edges, in order of modules.
+// Graph can have several, e.g. after dropping modules or when it is read from JSONL.
+func (g Graph) Roots() []string {
+	hasParent := map[string]bool{}
+	for _, e := range g.Edges {
+		hasParent[e.To] = true
+	}
+	var roots []string
+	for _, n := range g.Modules {
+		if !hasParent[n.ModuleName] {
+			roots = append(roots, n.ModuleName)
+		}
+	}
+	return roots
+}
+
+// Children returns modules that given module depends on directly, in order of edges
+func (g Graph) Children(moduleName string) []string {
+	var children []string
+	for _, e := range g.Edges {
+		if e.From == moduleName {
+			children = append(children, e.To)
+		}
+	}
+	return children
+}
+
+// Reachable returns all modules reachable from given module, including itself
+func (g Graph) Reachable(moduleName string) map[string]bool {
+	adj := map[string][]string{}
+	for _, e := range g.Edges {
+		adj[e.From] = append(adj[e.From], e.To)
+	}
+
+	visited := map[string]bool{moduleName: true}
+	queue := []string{moduleName}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, next := range adj[curr] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return visited
+}
+
+// DirectDependenciesReaching returns direct dependencies of root through which target is reachable.
+// If target is direct dependency itself, it is included as well.
+func (g Graph) DirectDependenciesReaching(target string) []string {
+	var via []string
+	for _, d := range g.Children(g.Root()) {
+		if g.Reachable(d)[target] {
+			via = append(via, d)
+		}
+	}
+	sort.Strings(via)
+	return via
+}
+
+// Paths returns at most k shortest simple paths from one module to another, shortest first, by Yen's algorithm.
+// Paths of same length are in order they are found, first of them is in order of edges.
+// Returns nil when k is not positive, since number of all paths can be exponential in size of graph.
+func (g Graph) Paths(from, to string, k int) [][]string {
+	if k <= 0 {
+		return nil
+	}
+	adj := map[string][]string{}
+	for _, e := range g.Edges {
+		adj[e.From] = append(adj[e.From], e.To)
+	}
+
+	first := shortestPath(adj, from, to, nil, nil)
+	if first == nil {
+		return nil
+	}
+	paths := [][]string{first}
+	var candidates [][]string
+
+	for len(paths) < k {
+		prev := paths[len(paths)-1]
+		for i := 0; i < len(prev)-1; i++ {
+			root := prev[:i+1]
+
+			// next path deviates from every found path with same root at spur node
+			skipEdges := map[[2]string]bool{}
+			for _, p := range paths {
+				if len(p) > i+1 && equal(p[:i+1], root) {
+					skipEdges[[2]string{p[i], p[i+1]}] = true
+				}
+			}
+			skipModules := map[string]bool{}
+			for _, m := range root[:i] {
+				skipModules[m] = true
+			}
+
+			spur := shortestPath(adj, root[i], to, skipModules, skipEdges)
+			if spur == nil {
+				continue
+			}
+			path := make([]string, 0, i+len(spur))
+			path = append(append(path, root[:i]...), spur...)
+			if !containsPath(paths, path) && !containsPath(candidates, path) {
+				candidates = append(candidates, path)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+
+		best := 0
+		for i, c := range candidates {
+			if len(c) < len(candidates[best]) {
+				best = i
+			}
+		}
+		paths = append(paths, candidates[best])
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+	return paths
+}
+
+// shortestPath finds path with fewest edges by breadth-first search, nil if there is none
+func shortestPath(adj map[string][]string, from, to string, skipModules map[string]bool, skipEdges map[[2]string]bool) []string {
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 && queue[0] != to {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, next := range adj[curr] {
+			if _, ok := prev[next]; ok || skipModules[next] || skipEdges[[2]string{curr, next}] {
+				continue
+			}
+			prev[next] = curr
+			queue = append(queue, next)
+		}
+	}
+	if _, ok := prev[to]; !ok {
+		return nil
+	}
+
+	path := []string{to}
+	for m := to; m != from; {
+		m = prev[m]
+		path = append(path, m)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsPath(paths [][]string, path []string) bool {
+	for _, p := range paths {
+		if equal(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Owner is repository owner of module, e.g. github.com/gin-gonic for github.com/gin-gonic/gin.
+// Module without owner in path, e.g. gopkg.in/yaml.v3, is owner itself.
+func Owner(moduleName string) string {
+	parts := strings.Split(moduleName, "/")
+	if len(parts) < 3 {
+		return moduleName
+	}
+	return strings.Join(parts[:2], "/")
+}
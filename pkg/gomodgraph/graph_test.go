@@ -0,0 +1,122 @@
+package gomodgraph
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaths(t *testing.T) {
+	// root -> a -> c -> d
+	// root -> b -> c
+	// root -> b -> d
+	// root -> e
+	g := Graph{
+		Modules: []Node{{ModuleName: "root"}, {ModuleName: "a"}, {ModuleName: "b"}, {ModuleName: "c"}, {ModuleName: "d"}, {ModuleName: "e"}},
+		Edges: []Edge{
+			{From: "root", To: "a"},
+			{From: "root", To: "b"},
+			{From: "root", To: "e"},
+			{From: "a", To: "c"},
+			{From: "b", To: "c"},
+			{From: "b", To: "d"},
+			{From: "c", To: "d"},
+			{From: "d", To: "b"},
+		},
+	}
+
+	t.Run("all", func(t *testing.T) {
+		assert.Equal(t, [][]string{
+			{"root", "b", "d"},
+			{"root", "a", "c", "d"},
+			{"root", "b", "c", "d"},
+		}, g.Paths("root", "d", 10))
+	})
+
+	t.Run("k shortest", func(t *testing.T) {
+		assert.Equal(t, [][]string{{"root", "b", "d"}}, g.Paths("root", "d", 1))
+	})
+
+	t.Run("not reachable", func(t *testing.T) {
+		assert.Nil(t, g.Paths("e", "d", 5))
+	})
+
+	t.Run("to itself", func(t *testing.T) {
+		assert.Equal(t, [][]string{{"root"}}, g.Paths("root", "root", 5))
+	})
+
+	t.Run("not positive k", func(t *testing.T) {
+		assert.Nil(t, g.Paths("root", "d", 0))
+	})
+}
+
+func TestPathsLayered(t *testing.T) {
+	// root -> 5 layers of 11 modules, every module depends on every module of next layer, last layer depends on target.
+	// There are 11^5 paths, only k shortest of them are listed.
+	g := Graph{Modules: []Node{{ModuleName: "root"}, {ModuleName: "target"}}}
+	name := func(layer, i int) string { return fmt.Sprintf("m%d_%d", layer, i) }
+	for layer := 0; layer < 5; layer++ {
+		for i := 0; i < 11; i++ {
+			g.Modules = append(g.Modules, Node{ModuleName: name(layer, i)})
+			switch {
+			case layer == 0:
+				g.Edges = append(g.Edges, Edge{From: "root", To: name(layer, i)})
+			case layer == 4:
+				g.Edges = append(g.Edges, Edge{From: name(layer, i), To: "target"})
+			}
+			if layer < 4 {
+				for j := 0; j < 11; j++ {
+					g.Edges = append(g.Edges, Edge{From: name(layer, i), To: name(layer+1, j)})
+				}
+			}
+		}
+	}
+	// shortcut makes single shortest path
+	g.Edges = append(g.Edges, Edge{From: "m1_3", To: "target"})
+
+	paths := g.Paths("root", "target", 5)
+	assert.Len(t, paths, 5)
+	assert.Equal(t, []string{"root", "m0_0", "m1_3", "target"}, paths[0])
+	for i, p := range paths {
+		assert.Equal(t, "root", p[0])
+		assert.Equal(t, "target", p[len(p)-1])
+		if i > 0 {
+			assert.LessOrEqual(t, len(paths[i-1]), len(p))
+			assert.NotEqual(t, paths[i-1], p)
+		}
+	}
+	assert.Len(t, paths[len(paths)-1], 4)
+}
+
+func TestOwner(t *testing.T) {
+	tests := map[string]string{
+		"github.com/gin-gonic/gin":               "github.com/gin-gonic",
+		"github.com/go-playground/validator/v10": "github.com/go-playground",
+		"golang.org/x/sys":                       "golang.org/x",
+		"gopkg.in/yaml.v3":                       "gopkg.in/yaml.v3",
+		"gopkg.in/src-d/go-git.v4":               "gopkg.in/src-d",
+		"go":                                     "go",
+	}
+	for module, owner := range tests {
+		assert.Equal(t, owner, Owner(module), module)
+	}
+}
+
+func TestRoots(t *testing.T) {
+	g := Graph{
+		Modules: []Node{{ModuleName: "app"}, {ModuleName: "a"}, {ModuleName: "tool"}, {ModuleName: "b"}},
+		Edges:   []Edge{{From: "app", To: "a"}, {From: "tool", To: "b"}},
+	}
+	assert.Equal(t, []string{"app", "tool"}, g.Roots())
+	assert.Equal(t, "app", g.Root())
+
+	cycle := Graph{
+		Modules: []Node{{ModuleName: "a"}, {ModuleName: "b"}},
+		Edges:   []Edge{{From: "a", To: "b"}, {From: "b", To: "a"}},
+	}
+	assert.Empty(t, cycle.Roots())
+	assert.Equal(t, "a", cycle.Root())
+
+	assert.Equal(t, "", Graph{}.Root())
+}
@@ -14,6 +14,12 @@ type Edge struct {
 
 type Node struct {
 	ModuleName string
+	Version    string // selected version, highest of all versions in graph, empty for main module
+
+	// known only when graph is built from compiled binary
+	Sum           string            // go.sum hash
+	Replace       string            // replace directive that was applied, e.g. "a v1.0.0 => b v1.0.1"
+	BuildSettings map[string]string // only for main module
 }
 
 type Graph struct {
@@ -29,23 +35,26 @@ func (c GoModGraphParser) Parse(input io.Reader) (Graph, error) {
 	scanner := bufio.NewScanner(input)
 
 	edgeAdded := map[Edge]bool{}
-	nodeAdded := map[string]bool{}
+	nodeIdx := map[string]int{}
 	var graph Graph
 	for scanner.Scan() {
 		from, to := processLine(scanner.Text())
 
-		if !edgeAdded[Edge{From: from, To: to}] {
-			graph.Edges = append(graph.Edges, Edge{From: from, To: to})
-			edgeAdded[Edge{From: from, To: to}] = true
+		if !edgeAdded[Edge{From: from.ModuleName, To: to.ModuleName}] {
+			graph.Edges = append(graph.Edges, Edge{From: from.ModuleName, To: to.ModuleName})
+			edgeAdded[Edge{From: from.ModuleName, To: to.ModuleName}] = true
 		}
 
-		if !nodeAdded[from] {
-			graph.Modules = append(graph.Modules, Node{ModuleName: from})
-			nodeAdded[from] = true
-		}
-		if !nodeAdded[to] {
-			graph.Modules = append(graph.Modules, Node{ModuleName: to})
-			nodeAdded[to] = true
+		for _, n := range []Node{from, to} {
+			idx, ok := nodeIdx[n.ModuleName]
+			if !ok {
+				graph.Modules = append(graph.Modules, n)
+				nodeIdx[n.ModuleName] = len(graph.Modules) - 1
+				continue
+			}
+			if CompareVersions(n.Version, graph.Modules[idx].Version) > 0 {
+				graph.Modules[idx].Version = n.Version
+			}
 		}
 	}
 	if err := scanner.Err(); err != nil {
@@ -56,18 +65,18 @@ func (c GoModGraphParser) Parse(input io.Reader) (Graph, error) {
 }
 
 // processLine parses single line of go mod graph output
-func processLine(line string) (from, to string) {
+func processLine(line string) (from, to Node) {
 	vNames := strings.Split(strings.TrimSpace(line), " ")
 	if len(vNames) < 2 {
-		return "", ""
+		return Node{}, Node{}
 	}
-	return getNameFromVersioned(vNames[0]), getNameFromVersioned(vNames[1])
+	return newNodeFromVersioned(vNames[0]), newNodeFromVersioned(vNames[1])
 }
 
-func getNameFromVersioned(versioned string) string {
-	parts := strings.Split(versioned, "@")
-	if len(parts) == 0 {
-		return ""
+func newNodeFromVersioned(versioned string) Node {
+	parts := strings.SplitN(versioned, "@", 2)
+	if len(parts) == 1 {
+		return Node{ModuleName: parts[0]}
 	}
-	return parts[0]
+	return Node{ModuleName: parts[0], Version: parts[1]}
 }
@@ -0,0 +1,89 @@
+package gomodgraph
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compares Go module versions by semver precedence, returns -1, 0 or 1.
+// Empty version is lower than any other version.
+func CompareVersions(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return -1
+	case b == "":
+		return 1
+	}
+
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+	for i := 0; i < 3; i++ {
+		if c := compareNumeric(aCore[i], bCore[i]); c != 0 {
+			return c
+		}
+	}
+
+	// version without prerelease has higher precedence
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+
+	aIDs, bIDs := strings.Split(aPre, "."), strings.Split(bPre, ".")
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		_, aErr := strconv.ParseUint(aIDs[i], 10, 64)
+		_, bErr := strconv.ParseUint(bIDs[i], 10, 64)
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareNumeric(aIDs[i], bIDs[i])
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(aIDs[i], bIDs[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareNumeric(strconv.Itoa(len(aIDs)), strconv.Itoa(len(bIDs)))
+}
+
+// splitVersion splits v1.2.3-pre+build into major, minor, patch and prerelease, build metadata is dropped
+func splitVersion(v string) (core [3]string, pre string) {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.Index(v, "+"); i >= 0 {
+		v = v[:i]
+	}
+	if i := strings.Index(v, "-"); i >= 0 {
+		v, pre = v[:i], v[i+1:]
+	}
+	parts := strings.SplitN(v, ".", 3)
+	for i := range core {
+		core[i] = "0"
+		if i < len(parts) {
+			core[i] = parts[i]
+		}
+	}
+	return core, pre
+}
+
+// compareNumeric compares decimal strings of any length
+func compareNumeric(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
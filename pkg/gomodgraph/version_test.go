@@ -0,0 +1,28 @@
+package gomodgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		exp  int
+	}{
+		{a: "v1.2.3", b: "v1.2.3", exp: 0},
+		{a: "v1.10.0", b: "v1.9.0", exp: 1},
+		{a: "v1.0.0-rc.1", b: "v1.0.0", exp: -1},
+		{a: "v1.0.0-rc.2", b: "v1.0.0-rc.10", exp: -1},
+		{a: "v0.0.0-20210101000000-abcdef123456", b: "v0.0.0-20200101000000-abcdef123456", exp: 1},
+		{a: "v2.0.0+incompatible", b: "v1.9.9", exp: 1},
+		{a: "", b: "v0.0.1", exp: -1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.a+" "+tc.b, func(t *testing.T) {
+			assert.Equal(t, tc.exp, CompareVersions(tc.a, tc.b))
+			assert.Equal(t, -tc.exp, CompareVersions(tc.b, tc.a))
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 type GoURLResolver interface {
 	ResolveGitHubURL(name string) (url.URL, error)
 	ResolveGitURL(name string) (url.URL, error)
+	GitLabURL(gitURL url.URL) (url.URL, error)
 }
 
 // GoCachedResolver caches GoURLResolver
@@ -23,6 +24,17 @@ func (c *GoCachedResolver) ResolveGitHubURL(name string) (url.URL, error) {
 	return c.tryLoad(name, newKeyGitHubURLKey(name), c.URLResolver.ResolveGitHubURL)
 }
 
+// ResolveGitLabURL cached version, it reuses cached git URL
+func (c *GoCachedResolver) ResolveGitLabURL(name string) (url.URL, error) {
+	return c.tryLoad(name, newKeyGitLabURLKey(name), func(name string) (url.URL, error) {
+		gitURL, err := c.ResolveGitURL(name)
+		if err != nil {
+			return url.URL{}, err
+		}
+		return c.URLResolver.GitLabURL(gitURL)
+	})
+}
+
 // ResolveGitURL cached version
 func (c *GoCachedResolver) ResolveGitURL(name string) (url.URL, error) {
 	return c.tryLoad(name, newKeyGitURLKey(name), c.URLResolver.ResolveGitURL)
@@ -53,6 +65,10 @@ func newKeyGitHubURLKey(name string) key {
 	return key("GitHubURL: " + name)
 }
 
+func newKeyGitLabURLKey(name string) key {
+	return key("GitLabURL: " + name)
+}
+
 func newKeyGitURLKey(name string) key {
 	return key("GitURL: " + name)
 }
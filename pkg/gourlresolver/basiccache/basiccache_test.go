@@ -0,0 +1,43 @@
+package basiccache
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingResolver struct {
+	numGitURL int
+}
+
+func (c *countingResolver) ResolveGitHubURL(name string) (url.URL, error) {
+	return url.URL{}, errors.New("not on GitHub")
+}
+
+func (c *countingResolver) ResolveGitURL(name string) (url.URL, error) {
+	c.numGitURL++
+	return url.URL{Scheme: "https", Host: "gitlab.com", Path: "/group/project.git"}, nil
+}
+
+func (c *countingResolver) GitLabURL(gitURL url.URL) (url.URL, error) {
+	gitURL.Path = "/group/project"
+	return gitURL, nil
+}
+
+func TestResolveGitLabURLReusesGitURL(t *testing.T) {
+	resolver := &countingResolver{}
+	cache := GoCachedResolver{URLResolver: resolver}
+
+	gitURL, err := cache.ResolveGitURL("example.com/project")
+	require.NoError(t, err)
+	assert.Equal(t, "https://gitlab.com/group/project.git", gitURL.String())
+
+	gitLabURL, err := cache.ResolveGitLabURL("example.com/project")
+	require.NoError(t, err)
+	assert.Equal(t, "https://gitlab.com/group/project", gitLabURL.String())
+
+	assert.Equal(t, 1, resolver.numGitURL)
+}
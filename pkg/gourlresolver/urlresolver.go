@@ -6,17 +6,73 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 )
 
 // GoURLResolver find Git and GitHub URLs for a Go module
 type GoURLResolver struct {
-	HTTPClient *http.Client
+	HTTPClient  *http.Client
+	GitHubHosts []string // GitHub Enterprise hosts, besides github.com, e.g. github.example.com
+	GitLabHosts []string // self-managed GitLab hosts, besides gitlab.com, e.g. gitlab.example.com
+	RepoHosts   []string // other hostings where repository is owner and name, e.g. codeberg.org
+}
+
+// isGitHubHost is true for github.com and configured GitHub Enterprise hosts
+func (c GoURLResolver) isGitHubHost(host string) bool {
+	if strings.EqualFold(host, "github.com") {
+		return true
+	}
+	for _, h := range c.GitHubHosts {
+		if strings.EqualFold(host, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGitLabHost is true for gitlab.com and configured self-managed GitLab hosts
+func (c GoURLResolver) isGitLabHost(host string) bool {
+	if strings.EqualFold(host, "gitlab.com") {
+		return true
+	}
+	for _, h := range c.GitLabHosts {
+		if strings.EqualFold(host, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOnGitLab is true when module name starts with GitLab host
+func (c GoURLResolver) isOnGitLab(name string) bool {
+	parts := strings.Split(name, "/")
+	return len(parts) >= 3 && c.isGitLabHost(parts[0])
+}
+
+// isOnRepoHost is true when module name starts with one of other hostings
+func (c GoURLResolver) isOnRepoHost(name string) bool {
+	parts := strings.Split(name, "/")
+	if len(parts) < 3 {
+		return false
+	}
+	for _, h := range c.RepoHosts {
+		if strings.EqualFold(parts[0], h) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOnGitHub is true when module name starts with GitHub host, then it is repository path already
+func (c GoURLResolver) isOnGitHub(name string) bool {
+	parts := strings.Split(name, "/")
+	return len(parts) >= 3 && c.isGitHubHost(parts[0])
 }
 
 // ResolveGitHubURL finds GitHub URL
 func (c GoURLResolver) ResolveGitHubURL(name string) (url.URL, error) {
-	if strings.HasPrefix(name, "github.com/") {
+	if c.isOnGitHub(name) {
 		return resolvePointerURL(url.Parse("https://" + normalizeGitURLPath(name)))
 	}
 	resp, err := c.fetchData(name)
@@ -27,22 +83,55 @@ func (c GoURLResolver) ResolveGitHubURL(name string) (url.URL, error) {
 	if err != nil {
 		return url.URL{}, fmt.Errorf("can not parse response: %w", err)
 	}
-	if gitURL.Host != "github.com" {
+	if !c.isGitHubHost(gitURL.Host) {
 		return url.URL{}, fmt.Errorf("git is not on GitHub: %v", gitURL)
 	}
 	return *gitURL, nil
 }
 
-// ResolveGitURL finds git URL
+// ResolveGitLabURL finds GitLab URL
+func (c GoURLResolver) ResolveGitLabURL(name string) (url.URL, error) {
+	gitURL, err := c.ResolveGitURL(name)
+	if err != nil {
+		return url.URL{}, err
+	}
+	return c.GitLabURL(gitURL)
+}
+
+// GitLabURL returns URL of GitLab project from its git URL, so that git URL that is already resolved can be reused
+func (c GoURLResolver) GitLabURL(gitURL url.URL) (url.URL, error) {
+	if !c.isGitLabHost(gitURL.Host) {
+		return url.URL{}, fmt.Errorf("git is not on GitLab: %v", gitURL)
+	}
+	gitURL.Path = strings.TrimSuffix(gitURL.Path, ".git")
+	return gitURL, nil
+}
+
+// ResolveGitURL finds git URL.
+// GitLab projects can be in nested groups, so for modules on GitLab hosts go-get metadata tells which part of path is project.
+// GitLab does not tell it for private projects without authentication, then whole module path without major version suffix is path of project.
 func (c GoURLResolver) ResolveGitURL(name string) (url.URL, error) {
-	if strings.HasPrefix(name, "github.com/") {
+	if c.isOnGitHub(name) {
+		return resolvePointerURL(url.Parse("https://" + normalizeGitURLPath(name)))
+	}
+	if c.isOnRepoHost(name) {
 		return resolvePointerURL(url.Parse("https://" + normalizeGitURLPath(name)))
 	}
+	if c.isOnGitLab(name) && strings.Contains(name+"/", ".git/") {
+		// go command does not look up module with VCS qualifier either
+		return resolvePointerURL(url.Parse("https://" + gitLabProjectPath(name)))
+	}
 	resp, err := c.fetchData(name)
-	if err != nil {
-		return url.URL{}, fmt.Errorf("can not make GET to Go module name: %w", err)
+	if err == nil {
+		var gitURL *url.URL
+		if gitURL, err = parseResponse(resp); err == nil {
+			return *gitURL, nil
+		}
+	}
+	if c.isOnGitLab(name) {
+		return resolvePointerURL(url.Parse("https://" + gitLabProjectPath(name)))
 	}
-	return resolvePointerURL(parseResponse(resp))
+	return url.URL{}, fmt.Errorf("can not resolve git URL: %w", err)
 }
 
 func normalizeGitURLPath(path string) string {
@@ -50,6 +139,17 @@ func normalizeGitURLPath(path string) string {
 	return strings.Join(parts[:3], "/")
 }
 
+var majorVersionSuffix = regexp.MustCompile(`/v[0-9]+$`)
+
+// gitLabProjectPath drops major version suffix and anything after .git qualifier, e.g. gitlab.com/a/b/c.git/d becomes gitlab.com/a/b/c
+func gitLabProjectPath(name string) string {
+	if i := strings.Index(name, ".git/"); i >= 0 {
+		return name[:i]
+	}
+	name = strings.TrimSuffix(name, ".git")
+	return majorVersionSuffix.ReplaceAllString(name, "")
+}
+
 func (c GoURLResolver) fetchData(name string) (string, error) {
 	resp, err := c.HTTPClient.Get("https://" + name + "?go-get=1")
 	if err != nil {
@@ -0,0 +1,100 @@
+package gourlresolver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGitLab serves go-get metadata of single project, like GitLab does for public projects.
+// Modules that are not in project are not found, like private projects without authentication.
+func newGitLab(t *testing.T, project string) (*httptest.Server, string) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("go-get") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		host := r.Host
+		if !strings.HasPrefix(r.URL.Path, "/"+project) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `<html><head><meta name="go-import" content="%s/%s git https://%s/%s.git"></head></html>`, host, project, host, project)
+	}))
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return server, u.Host
+}
+
+func TestResolveGitLabURL(t *testing.T) {
+	server, host := newGitLab(t, "group/project")
+	resolver := GoURLResolver{HTTPClient: server.Client(), GitLabHosts: []string{host}}
+
+	tests := []struct {
+		name      string
+		module    string
+		gitURL    string
+		gitLabURL string
+	}{
+		{
+			name:      "project",
+			module:    host + "/group/project",
+			gitURL:    "https://" + host + "/group/project.git",
+			gitLabURL: "https://" + host + "/group/project",
+		},
+		{
+			name:      "module in subdirectory",
+			module:    host + "/group/project/submodule",
+			gitURL:    "https://" + host + "/group/project.git",
+			gitLabURL: "https://" + host + "/group/project",
+		},
+		{
+			name:      "private project in nested groups",
+			module:    host + "/group/subgroup/private/v2",
+			gitURL:    "https://" + host + "/group/subgroup/private",
+			gitLabURL: "https://" + host + "/group/subgroup/private",
+		},
+		{
+			name:      "VCS qualifier",
+			module:    host + "/platform/go/tools.git/subpkg",
+			gitURL:    "https://" + host + "/platform/go/tools",
+			gitLabURL: "https://" + host + "/platform/go/tools",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := resolver.ResolveGitURL(tc.module)
+			require.NoError(t, err)
+			assert.Equal(t, tc.gitURL, u.String())
+
+			u, err = resolver.ResolveGitLabURL(tc.module)
+			require.NoError(t, err)
+			assert.Equal(t, tc.gitLabURL, u.String())
+		})
+	}
+}
+
+func TestResolveGitLabURLNotOnGitLab(t *testing.T) {
+	// any request fails test, GitHub and other hostings do not need go-get metadata
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected request %s", r.URL)
+		return nil, fmt.Errorf("unexpected request")
+	})}
+	resolver := GoURLResolver{HTTPClient: client, RepoHosts: []string{"codeberg.org"}}
+
+	for _, module := range []string{"github.com/gin-gonic/gin", "github.com/go-playground/validator/v10", "codeberg.org/owner/name"} {
+		_, err := resolver.ResolveGitLabURL(module)
+		assert.Error(t, err, module)
+	}
+}
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
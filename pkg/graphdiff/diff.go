@@ -0,0 +1,260 @@
+// Package graphdiff compares two graphs, for example before and after change in pull request
+package graphdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// Module is module that was added or removed
+type Module struct {
+	Module  string                 `json:"module"`
+	Version string                 `json:"version,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// Delta is change of single field
+type Delta struct {
+	Field  string      `json:"field"`
+	From   interface{} `json:"from"`
+	To     interface{} `json:"to"`
+	Change *float64    `json:"change,omitempty"` // for numbers
+}
+
+// Change is module that is in both graphs and has different version or fields
+type Change struct {
+	Module      string  `json:"module"`
+	FromVersion string  `json:"from_version,omitempty"`
+	ToVersion   string  `json:"to_version,omitempty"`
+	Direction   string  `json:"direction,omitempty"` // upgrade, downgrade or empty if version is same
+	Deltas      []Delta `json:"deltas,omitempty"`
+}
+
+// Diff is difference between two graphs
+type Diff struct {
+	Added        []Module         `json:"added"`
+	Removed      []Module         `json:"removed"`
+	Changed      []Change         `json:"changed"`
+	AddedEdges   []collector.Edge `json:"added_edges"`
+	RemovedEdges []collector.Edge `json:"removed_edges"`
+}
+
+// IsEmpty is true when graphs are same
+func (d Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// Comparer compares graphs
+type Comparer struct {
+	Fields []string // fields to track, e.g. health_score
+}
+
+// Compare finds what changed from old to current graph.
+// Modules are changed when version or any of tracked fields are different.
+func (c Comparer) Compare(old, curr collector.Graph) (Diff, error) {
+	diff := Diff{Added: []Module{}, Removed: []Module{}, Changed: []Change{}, AddedEdges: []collector.Edge{}, RemovedEdges: []collector.Edge{}}
+
+	oldFields, err := fieldsByID(old)
+	if err != nil {
+		return diff, err
+	}
+	currFields, err := fieldsByID(curr)
+	if err != nil {
+		return diff, err
+	}
+
+	for _, m := range curr.Modules {
+		prev, ok := oldFields[m.ID]
+		if !ok {
+			diff.Added = append(diff.Added, c.module(m.ID, currFields[m.ID]))
+			continue
+		}
+		if change, ok := c.change(m.ID, prev, currFields[m.ID]); ok {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for _, m := range old.Modules {
+		if _, ok := currFields[m.ID]; !ok {
+			diff.Removed = append(diff.Removed, c.module(m.ID, oldFields[m.ID]))
+		}
+	}
+
+	diff.AddedEdges = edgesNotIn(curr.Edges, old.Edges)
+	diff.RemovedEdges = edgesNotIn(old.Edges, curr.Edges)
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Module < diff.Added[j].Module })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Module < diff.Removed[j].Module })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Module < diff.Changed[j].Module })
+	return diff, nil
+}
+
+func (c Comparer) module(id string, fields map[string]interface{}) Module {
+	m := Module{Module: id}
+	m.Version, _ = fields["version"].(string)
+	for _, f := range c.Fields {
+		if v, ok := fields[f]; ok && v != nil {
+			if m.Fields == nil {
+				m.Fields = map[string]interface{}{}
+			}
+			m.Fields[f] = v
+		}
+	}
+	return m
+}
+
+func (c Comparer) change(id string, old, curr map[string]interface{}) (Change, bool) {
+	change := Change{Module: id}
+	change.FromVersion, _ = old["version"].(string)
+	change.ToVersion, _ = curr["version"].(string)
+	switch cmp := gomodgraph.CompareVersions(change.FromVersion, change.ToVersion); {
+	case cmp < 0:
+		change.Direction = "upgrade"
+	case cmp > 0:
+		change.Direction = "downgrade"
+	}
+
+	for _, f := range c.Fields {
+		from, to := old[f], curr[f]
+		if fmt.Sprint(from) == fmt.Sprint(to) {
+			continue
+		}
+		d := Delta{Field: f, From: from, To: to}
+		if a, ok := from.(float64); ok {
+			if b, ok := to.(float64); ok {
+				v := math.Round((b-a)*100) / 100
+				d.Change = &v
+			}
+		}
+		change.Deltas = append(change.Deltas, d)
+	}
+
+	return change, change.FromVersion != change.ToVersion || len(change.Deltas) > 0
+}
+
+func fieldsByID(g collector.Graph) (map[string]map[string]interface{}, error) {
+	rows, err := g.ModuleFields()
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]map[string]interface{}, len(rows))
+	for i, m := range g.Modules {
+		fields[m.ID] = rows[i]
+	}
+	return fields, nil
+}
+
+// edgesNotIn returns edges of a that are not in b, in order of a
+func edgesNotIn(a, b []collector.Edge) []collector.Edge {
+	inB := make(map[collector.Edge]bool, len(b))
+	for _, e := range b {
+		inB[e] = true
+	}
+	diff := []collector.Edge{}
+	for _, e := range a {
+		if !inB[e] {
+			diff = append(diff, e)
+			inB[e] = true
+		}
+	}
+	return diff
+}
+
+// WriteJSON writes diff as single JSON object
+func (d Diff) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+// WriteMarkdown writes diff for pull request comment
+func (d Diff) WriteMarkdown(w io.Writer, fields []string) error {
+	var b strings.Builder
+	b.WriteString("## Dependency changes\n\n")
+	if d.IsEmpty() {
+		b.WriteString("No changes.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	fmt.Fprintf(&b, "%d added, %d removed, %d changed modules, %d added, %d removed edges\n", len(d.Added), len(d.Removed), len(d.Changed), len(d.AddedEdges), len(d.RemovedEdges))
+
+	for _, section := range []struct {
+		title   string
+		modules []Module
+	}{{"Added", d.Added}, {"Removed", d.Removed}} {
+		if len(section.modules) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", section.title)
+		b.WriteString(row(append([]string{"module", "version"}, fields...)))
+		b.WriteString(separator(2 + len(fields)))
+		for _, m := range section.modules {
+			cells := []string{code(m.Module), m.Version}
+			for _, f := range fields {
+				cells = append(cells, formatValue(m.Fields[f]))
+			}
+			b.WriteString(row(cells))
+		}
+	}
+
+	if len(d.Changed) > 0 {
+		b.WriteString("\n### Changed\n\n")
+		b.WriteString(row(append([]string{"module", "from", "to", ""}, fields...)))
+		b.WriteString(separator(4 + len(fields)))
+		for _, c := range d.Changed {
+			deltas := map[string]Delta{}
+			for _, d := range c.Deltas {
+				deltas[d.Field] = d
+			}
+			cells := []string{code(c.Module), c.FromVersion, c.ToVersion, c.Direction}
+			for _, f := range fields {
+				cells = append(cells, formatDelta(deltas[f]))
+			}
+			b.WriteString(row(cells))
+		}
+	}
+
+	if len(d.AddedEdges) > 0 || len(d.RemovedEdges) > 0 {
+		b.WriteString("\n### Edges\n\n")
+		for _, e := range d.AddedEdges {
+			fmt.Fprintf(&b, "- added %s → %s\n", code(e.From), code(e.To))
+		}
+		for _, e := range d.RemovedEdges {
+			fmt.Fprintf(&b, "- removed %s → %s\n", code(e.From), code(e.To))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func row(cells []string) string { return "| " + strings.Join(cells, " | ") + " |\n" }
+
+func separator(n int) string { return "|" + strings.Repeat(" --- |", n) + "\n" }
+
+func code(s string) string { return "`" + s + "`" }
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return strings.ReplaceAll(fmt.Sprint(v), "|", `\|`)
+}
+
+func formatDelta(d Delta) string {
+	if d.Field == "" {
+		return ""
+	}
+	s := formatValue(d.From) + " → " + formatValue(d.To)
+	if d.Change != nil {
+		s += fmt.Sprintf(" (%+g)", *d.Change)
+	}
+	return s
+}
@@ -0,0 +1,70 @@
+package graphdiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func TestCompare(t *testing.T) {
+	old := collector.Graph{
+		Modules: []collector.ModuleStats{
+			{ID: "root"},
+			{ID: "a", Version: "v1.0.0", HealthStats: &collector.HealthStats{Score: 80}},
+			{ID: "b", Version: "v1.2.0", HealthStats: &collector.HealthStats{Score: 50}},
+			{ID: "c", Version: "v0.1.0"},
+		},
+		Edges: []collector.Edge{{From: "root", To: "a"}, {From: "root", To: "b"}, {From: "b", To: "c"}},
+	}
+	curr := collector.Graph{
+		Modules: []collector.ModuleStats{
+			{ID: "root"},
+			{ID: "a", Version: "v1.1.0", HealthStats: &collector.HealthStats{Score: 90.5}},
+			{ID: "b", Version: "v1.2.0", HealthStats: &collector.HealthStats{Score: 50}},
+			{ID: "d", Version: "v2.0.0", HealthStats: &collector.HealthStats{Score: 70}},
+		},
+		Edges: []collector.Edge{{From: "root", To: "a"}, {From: "root", To: "b"}, {From: "a", To: "d"}},
+	}
+
+	c := Comparer{Fields: []string{"health_score"}}
+	diff, err := c.Compare(old, curr)
+	require.NoError(t, err)
+
+	assert.Equal(t, Diff{
+		Added:   []Module{{Module: "d", Version: "v2.0.0", Fields: map[string]interface{}{"health_score": 70.0}}},
+		Removed: []Module{{Module: "c", Version: "v0.1.0"}},
+		Changed: []Change{{
+			Module:      "a",
+			FromVersion: "v1.0.0",
+			ToVersion:   "v1.1.0",
+			Direction:   "upgrade",
+			Deltas:      []Delta{{Field: "health_score", From: 80.0, To: 90.5, Change: ptr(10.5)}},
+		}},
+		AddedEdges:   []collector.Edge{{From: "a", To: "d"}},
+		RemovedEdges: []collector.Edge{{From: "b", To: "c"}},
+	}, diff)
+
+	var b bytes.Buffer
+	require.NoError(t, diff.WriteMarkdown(&b, c.Fields))
+	assert.Contains(t, b.String(), "| `a` | v1.0.0 | v1.1.0 | upgrade | 80 → 90.5 (+10.5) |")
+}
+
+func TestCompareSame(t *testing.T) {
+	g := collector.Graph{
+		Modules: []collector.ModuleStats{{ID: "root"}, {ID: "a", Version: "v1.0.0"}},
+		Edges:   []collector.Edge{{From: "root", To: "a"}},
+	}
+	diff, err := Comparer{}.Compare(g, g)
+	require.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+
+	var b bytes.Buffer
+	require.NoError(t, diff.WriteMarkdown(&b, nil))
+	assert.Equal(t, "## Dependency changes\n\nNo changes.\n", b.String())
+}
@@ -0,0 +1,69 @@
+package graphdiff
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// GitRevisionPrefix marks source that is go.mod at git revision, e.g. git:main
+const GitRevisionPrefix = "git:"
+
+// Load reads graph from source, which is either path to JSONL file or go.mod at git revision.
+// Graph from git revision has only modules and versions.
+func Load(source string) (collector.Graph, error) {
+	if rev := strings.TrimPrefix(source, GitRevisionPrefix); rev != source {
+		gmod, err := GoModGraphAtRevision(rev)
+		if err != nil {
+			return collector.Graph{}, err
+		}
+		var g collector.Graph
+		return g.Restructure(gmod), nil
+	}
+
+	f, err := os.Open(source)
+	if err != nil {
+		return collector.Graph{}, fmt.Errorf("can not open %s: %w", source, err)
+	}
+	defer func() { f.Close() }()
+	return collector.ReadJSONL(f)
+}
+
+// GoModGraphAtRevision runs go mod graph for go.mod and go.sum in current directory at git revision
+func GoModGraphAtRevision(rev string) (gomodgraph.Graph, error) {
+	dir, err := ioutil.TempDir("", "import-graph-diff")
+	if err != nil {
+		return gomodgraph.Graph{}, fmt.Errorf("can not create temporary directory: %w", err)
+	}
+	defer func() { os.RemoveAll(dir) }()
+
+	for _, name := range []string{"go.mod", "go.sum"} {
+		content, err := exec.Command("git", "show", rev+":./"+name).Output()
+		if err != nil {
+			if name == "go.sum" {
+				continue
+			}
+			return gomodgraph.Graph{}, fmt.Errorf("can not get %s at %s: %w", name, rev, err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			return gomodgraph.Graph{}, fmt.Errorf("can not write %s: %w", name, err)
+		}
+	}
+
+	cmd := exec.Command("go", "mod", "graph")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return gomodgraph.Graph{}, fmt.Errorf("can not run go mod graph at %s: %s: %w", rev, strings.TrimSpace(stderr.String()), err)
+	}
+	return gomodgraph.GoModGraphParser{}.Parse(bytes.NewReader(out))
+}
@@ -0,0 +1,72 @@
+// Package graphexport writes collected graph in formats of graph tools: GraphML, GEXF and Mermaid
+package graphexport
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+// typeEnum is type of node attribute, names are same in GraphML and GEXF
+type typeEnum string
+
+const (
+	typeBoolean typeEnum = "boolean"
+	typeLong    typeEnum = "long"
+	typeDouble  typeEnum = "double"
+	typeString  typeEnum = "string"
+)
+
+// attribute is typed node attribute
+type attribute struct {
+	Name string
+	Type typeEnum
+}
+
+// attributes are columns present in rows, typed by fields of ModuleStats, id is not included.
+// Type does not depend on values, so same field has same type in every output.
+func attributes(rows []map[string]interface{}) []attribute {
+	types := collector.ColumnTypes()
+	var attrs []attribute
+	for _, col := range collector.PresentColumns(rows) {
+		if col == "id" {
+			continue
+		}
+		attrs = append(attrs, attribute{Name: col, Type: typeOf(types[col])})
+	}
+	return attrs
+}
+
+// typeOf is attribute type of Go type, nested values and unknown fields are strings
+func typeOf(t reflect.Type) typeEnum {
+	if t == nil {
+		return typeString
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return typeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return typeLong
+	case reflect.Float32, reflect.Float64:
+		return typeDouble
+	default:
+		return typeString
+	}
+}
+
+// formatValue formats value of attribute, nested values are JSON
+func formatValue(v interface{}) string {
+	switch vv := v.(type) {
+	case string:
+		return vv
+	case bool:
+		return strconv.FormatBool(vv)
+	case float64:
+		return strconv.FormatFloat(vv, 'f', -1, 64)
+	default:
+		b, _ := json.Marshal(vv)
+		return string(b)
+	}
+}
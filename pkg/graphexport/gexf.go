@@ -0,0 +1,93 @@
+package graphexport
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+type gexf struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string         `xml:"defaultedgetype,attr"`
+	Mode            string         `xml:"mode,attr"`
+	Attributes      gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode     `xml:"nodes>node"`
+	Edges           []gexfEdge     `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type gexfEdge struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+// GEXFWriter writes graph in GEXF 1.3, which is native format of Gephi.
+// Every field of module is typed node attribute.
+type GEXFWriter struct{}
+
+// Write renders graph
+func (c GEXFWriter) Write(w io.Writer, g collector.Graph) error {
+	rows, err := g.ModuleFields()
+	if err != nil {
+		return err
+	}
+	attrs := attributes(rows)
+
+	doc := gexf{
+		XMLNS:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Graph: gexfGraph{
+			DefaultEdgeType: "directed",
+			Mode:            "static",
+			Attributes:      gexfAttributes{Class: "node"},
+		},
+	}
+	for i, a := range attrs {
+		doc.Graph.Attributes.Attributes = append(doc.Graph.Attributes.Attributes, gexfAttribute{ID: strconv.Itoa(i), Title: a.Name, Type: string(a.Type)})
+	}
+
+	for i, m := range g.Modules {
+		node := gexfNode{ID: m.ID, Label: m.ID}
+		for j, a := range attrs {
+			if v, ok := rows[i][a.Name]; ok && v != nil {
+				node.AttValues = append(node.AttValues, gexfAttValue{For: strconv.Itoa(j), Value: formatValue(v)})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	for i, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{ID: strconv.Itoa(i), Source: e.From, Target: e.To})
+	}
+
+	return writeXML(w, doc)
+}
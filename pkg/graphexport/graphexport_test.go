@@ -0,0 +1,88 @@
+package graphexport
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/collector/collectortest"
+	"github.com/nikolaydubina/import-graph/pkg/golden"
+)
+
+func TestWriters(t *testing.T) {
+	tests := []struct {
+		golden string
+		write  func(w io.Writer, g collector.Graph) error
+	}{
+		{golden: "graph.graphml", write: GraphMLWriter{}.Write},
+		{golden: "graph.gexf", write: GEXFWriter{}.Write},
+		{golden: "graph.mmd", write: MermaidWriter{}.Write},
+		{golden: "graph_depth_1.mmd", write: MermaidWriter{MaxDepth: 1}.Write},
+	}
+	for _, tc := range tests {
+		t.Run(tc.golden, func(t *testing.T) {
+			var b bytes.Buffer
+			require.NoError(t, tc.write(&b, collectortest.Graph()))
+			golden.Equal(t, tc.golden, b.Bytes())
+		})
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": "a", "can_run_tests": true, "health_score": 90.0, "git_num_contributors": 3.0, "license": "MIT", "health_score_breakdown": map[string]interface{}{"tests": 100.0}},
+		{"id": "b", "health_score": 42.5, "license_category": "permissive", "unknown": 1.0},
+	}
+	assert.Equal(t, []attribute{
+		{Name: "can_run_tests", Type: typeBoolean},
+		{Name: "git_num_contributors", Type: typeLong},
+		{Name: "license", Type: typeString},
+		{Name: "license_category", Type: typeString},
+		{Name: "health_score", Type: typeDouble},
+		{Name: "health_score_breakdown", Type: typeString},
+		{Name: "unknown", Type: typeString},
+	}, attributes(rows))
+
+	// whole numbers are still double when field is float
+	assert.Equal(t, []attribute{{Name: "health_score", Type: typeDouble}}, attributes([]map[string]interface{}{{"health_score": 90.0}}))
+
+	assert.Equal(t, `{"k":1}`, formatValue(map[string]interface{}{"k": 1.0}))
+	assert.Equal(t, "42.5", formatValue(42.5))
+	assert.Equal(t, "true", formatValue(true))
+}
+
+func TestMermaidWriterSeveralRoots(t *testing.T) {
+	// app -> a -> b, tool -> c
+	g := collector.Graph{
+		Modules: []collector.ModuleStats{{ID: "app"}, {ID: "a"}, {ID: "b"}, {ID: "tool"}, {ID: "c"}},
+		Edges:   []collector.Edge{{From: "app", To: "a"}, {From: "a", To: "b"}, {From: "tool", To: "c"}},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, MermaidWriter{}.Write(&b, g))
+	assert.Equal(t, `flowchart LR
+    n0["app"]
+    n1["a"]
+    n2["b"]
+    n3["tool"]
+    n4["c"]
+    n0 --> n1
+    n1 --> n2
+    n3 --> n4
+`, b.String())
+
+	b.Reset()
+	require.NoError(t, MermaidWriter{MaxDepth: 1}.Write(&b, g))
+	assert.Equal(t, `flowchart LR
+    n0["app"]
+    n1["a"]
+    n2["tool"]
+    n3["c"]
+    n0 --> n1
+    n2 --> n3
+`, b.String())
+}
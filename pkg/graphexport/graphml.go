@@ -0,0 +1,95 @@
+package graphexport
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+// GraphMLWriter writes graph in GraphML, which is used by yEd, Gephi and networkx.
+// Every field of module is typed node attribute.
+type GraphMLWriter struct{}
+
+// Write renders graph
+func (c GraphMLWriter) Write(w io.Writer, g collector.Graph) error {
+	rows, err := g.ModuleFields()
+	if err != nil {
+		return err
+	}
+	attrs := attributes(rows)
+
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys:  []graphMLKey{{ID: "label", For: "node", AttrName: "label", AttrType: string(typeString)}},
+		Graph: graphMLGraph{ID: "G", EdgeDefault: "directed"},
+	}
+	for i, a := range attrs {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: fmt.Sprintf("d%d", i), For: "node", AttrName: a.Name, AttrType: string(a.Type)})
+	}
+
+	for i, m := range g.Modules {
+		node := graphMLNode{ID: m.ID, Data: []graphMLData{{Key: "label", Value: m.ID}}}
+		for j, a := range attrs {
+			if v, ok := rows[i][a.Name]; ok && v != nil {
+				node.Data = append(node.Data, graphMLData{Key: fmt.Sprintf("d%d", j), Value: formatValue(v)})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: e.From, Target: e.To})
+	}
+
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("can not encode xml: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,76 @@
+package graphexport
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// MermaidWriter writes graph as Mermaid flowchart, which GitHub renders in markdown
+type MermaidWriter struct {
+	MaxDepth int // max distance from main module, 0 means no limit
+}
+
+// Write renders graph
+func (c MermaidWriter) Write(w io.Writer, g collector.Graph) error {
+	gmod := g.GoModGraph()
+	var depth map[string]int
+	if c.MaxDepth > 0 {
+		depth = distances(gmod, c.MaxDepth)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	ids := map[string]string{}
+	for _, m := range g.Modules {
+		if _, ok := depth[m.ID]; depth != nil && !ok {
+			continue
+		}
+		ids[m.ID] = fmt.Sprintf("n%d", len(ids))
+		fmt.Fprintf(&b, "    %s[\"%s\"]\n", ids[m.ID], escapeMermaid(m.ID))
+	}
+	for _, e := range g.Edges {
+		from, okFrom := ids[e.From]
+		to, okTo := ids[e.To]
+		if okFrom && okTo {
+			fmt.Fprintf(&b, "    %s --> %s\n", from, to)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// distances from modules without dependents to all modules within max distance.
+// Graph can have several such modules, e.g. after some modules are dropped.
+func distances(gmod gomodgraph.Graph, maxDepth int) map[string]int {
+	queue := gmod.Roots()
+	if len(queue) == 0 && len(gmod.Modules) > 0 {
+		queue = []string{gmod.Root()}
+	}
+	depth := make(map[string]int, len(queue))
+	for _, root := range queue {
+		depth[root] = 0
+	}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		if depth[curr] >= maxDepth {
+			continue
+		}
+		for _, next := range gmod.Children(curr) {
+			if _, ok := depth[next]; !ok {
+				depth[next] = depth[curr] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return depth
+}
+
+func escapeMermaid(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
@@ -0,0 +1,168 @@
+// Package graphmetrics computes structural metrics of every module in graph
+package graphmetrics
+
+import (
+	"math"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// Metrics is position of module in graph
+type Metrics struct {
+	Depth                     *uint   // shortest distance from main module, nil if not reachable
+	InDegree                  uint    // number of modules that depend on module directly
+	OutDegree                 uint    // number of direct dependencies
+	NumTransitiveDependencies uint    // number of modules reachable from module, excluding itself
+	NumDependents             uint    // number of modules from which module is reachable, excluding itself
+	Betweenness               float64 // share of shortest paths between other modules that go through module, from 0 to 1
+	ViaSingleDirectDependency string  // direct dependency of main module through which module is only reachable
+}
+
+// Compute returns metrics for every module in graph
+func Compute(g gomodgraph.Graph) map[string]Metrics {
+	var nodes []string
+	index := map[string]int{}
+	for _, n := range g.Modules {
+		if _, ok := index[n.ModuleName]; !ok {
+			index[n.ModuleName] = len(nodes)
+			nodes = append(nodes, n.ModuleName)
+		}
+	}
+
+	adj := make([][]int, len(nodes))
+	radj := make([][]int, len(nodes))
+	seen := map[gomodgraph.Edge]bool{}
+	for _, e := range g.Edges {
+		from, okFrom := index[e.From]
+		to, okTo := index[e.To]
+		if !okFrom || !okTo || seen[e] || from == to {
+			continue
+		}
+		seen[e] = true
+		adj[from] = append(adj[from], to)
+		radj[to] = append(radj[to], from)
+	}
+
+	metrics := make([]Metrics, len(nodes))
+	for i := range nodes {
+		metrics[i].InDegree = uint(len(radj[i]))
+		metrics[i].OutDegree = uint(len(adj[i]))
+		metrics[i].NumTransitiveDependencies = uint(countReachable(adj, i) - 1)
+		metrics[i].NumDependents = uint(countReachable(radj, i) - 1)
+	}
+
+	if root, ok := index[g.Root()]; ok {
+		for i, d := range distances(adj, root) {
+			if d >= 0 {
+				depth := uint(d)
+				metrics[i].Depth = &depth
+			}
+		}
+
+		// modules that are reachable only through single direct dependency
+		numVia := make([]int, len(nodes))
+		via := make([]int, len(nodes))
+		for _, d := range adj[root] {
+			for i, dist := range distances(adj, d) {
+				if dist >= 0 {
+					numVia[i]++
+					via[i] = d
+				}
+			}
+		}
+		for i := range nodes {
+			if numVia[i] == 1 && i != root {
+				metrics[i].ViaSingleDirectDependency = nodes[via[i]]
+			}
+		}
+	}
+
+	for i, b := range betweenness(adj) {
+		if n := float64(len(nodes)); n > 2 {
+			b /= (n - 1) * (n - 2)
+		}
+		metrics[i].Betweenness = math.Round(b*1e6) / 1e6
+	}
+
+	result := make(map[string]Metrics, len(nodes))
+	for i, name := range nodes {
+		result[name] = metrics[i]
+	}
+	return result
+}
+
+func countReachable(adj [][]int, from int) int {
+	count := 0
+	for _, d := range distances(adj, from) {
+		if d >= 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// distances from node to all other nodes by BFS, -1 if node is not reachable
+func distances(adj [][]int, from int) []int {
+	dist := make([]int, len(adj))
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[from] = 0
+	queue := []int{from}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, next := range adj[curr] {
+			if dist[next] < 0 {
+				dist[next] = dist[curr] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return dist
+}
+
+// betweenness centrality of directed unweighted graph by Brandes algorithm
+func betweenness(adj [][]int) []float64 {
+	n := len(adj)
+	centrality := make([]float64, n)
+	for s := 0; s < n; s++ {
+		var stack []int
+		preds := make([][]int, n)
+		sigma := make([]float64, n)
+		dist := make([]int, n)
+		for i := range dist {
+			dist[i] = -1
+		}
+		sigma[s], dist[s] = 1, 0
+
+		queue := []int{s}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, w := range adj[v] {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+
+		delta := make([]float64, n)
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s {
+				centrality[w] += delta[w]
+			}
+		}
+	}
+	return centrality
+}
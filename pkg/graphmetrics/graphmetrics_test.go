@@ -0,0 +1,57 @@
+package graphmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+func uptr(v uint) *uint { return &v }
+
+func TestCompute(t *testing.T) {
+	// root -> a -> c -> d
+	// root -> b -> c
+	// root -> b -> e
+	// x is not reachable
+	g := gomodgraph.Graph{
+		Modules: []gomodgraph.Node{{ModuleName: "root"}, {ModuleName: "a"}, {ModuleName: "b"}, {ModuleName: "c"}, {ModuleName: "d"}, {ModuleName: "e"}, {ModuleName: "x"}},
+		Edges: []gomodgraph.Edge{
+			{From: "root", To: "a"},
+			{From: "root", To: "b"},
+			{From: "a", To: "c"},
+			{From: "b", To: "c"},
+			{From: "b", To: "e"},
+			{From: "c", To: "d"},
+			{From: "x", To: "d"},
+		},
+	}
+
+	m := Compute(g)
+
+	assert.Equal(t, uptr(0), m["root"].Depth)
+	assert.Equal(t, uptr(1), m["a"].Depth)
+	assert.Equal(t, uptr(2), m["c"].Depth)
+	assert.Equal(t, uptr(3), m["d"].Depth)
+	assert.Nil(t, m["x"].Depth)
+
+	assert.Equal(t, uint(2), m["c"].InDegree)
+	assert.Equal(t, uint(1), m["c"].OutDegree)
+	assert.Equal(t, uint(5), m["root"].NumTransitiveDependencies)
+	assert.Equal(t, uint(3), m["b"].NumTransitiveDependencies)
+	assert.Equal(t, uint(5), m["d"].NumDependents)
+	assert.Equal(t, uint(0), m["root"].NumDependents)
+
+	assert.Equal(t, "", m["root"].ViaSingleDirectDependency)
+	assert.Equal(t, "", m["c"].ViaSingleDirectDependency)
+	assert.Equal(t, "", m["d"].ViaSingleDirectDependency)
+	assert.Equal(t, "b", m["e"].ViaSingleDirectDependency)
+	assert.Equal(t, "a", m["a"].ViaSingleDirectDependency)
+
+	// c is on shortest paths root->d, a->d, b->d, one of two paths for root->d
+	// pairs: (root,d) has 2 shortest paths both via c -> 1; (a,d) -> 1; (b,d) -> 1
+	assert.Equal(t, 3.0/30, m["c"].Betweenness)
+	assert.Equal(t, 0.0, m["root"].Betweenness)
+	assert.Equal(t, 0.0, m["x"].Betweenness)
+}
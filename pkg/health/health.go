@@ -0,0 +1,166 @@
+// Package health combines collected fields of module into single score
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// Point maps field value to score, scores between points are linearly interpolated
+type Point struct {
+	Value float64 `json:"value"`
+	Score float64 `json:"score"`
+}
+
+// Component scores single field from 0 to 100.
+// Numeric fields are scored by Points, other fields by Values, e.g. {"true": 100, "false": 0}.
+type Component struct {
+	Name    string             `json:"name"`
+	Field   string             `json:"field"`
+	Weight  float64            `json:"weight"`
+	Points  []Point            `json:"points,omitempty"`
+	Values  map[string]float64 `json:"values,omitempty"`
+	Missing *float64           `json:"missing,omitempty"` // score if field is missing, by default component is skipped
+}
+
+// score returns score of component and false if it can not be scored
+func (c Component) score(fields map[string]interface{}) (float64, bool) {
+	v, ok := fields[c.Field]
+	if !ok || v == nil {
+		if c.Missing != nil {
+			return *c.Missing, true
+		}
+		return 0, false
+	}
+
+	switch vv := v.(type) {
+	case float64:
+		if len(c.Points) > 0 {
+			return interpolate(c.Points, vv), true
+		}
+		s, ok := c.Values[strconv.FormatFloat(vv, 'f', -1, 64)]
+		return s, ok
+	case bool:
+		s, ok := c.Values[strconv.FormatBool(vv)]
+		return s, ok
+	case string:
+		s, ok := c.Values[vv]
+		return s, ok
+	default:
+		return 0, false
+	}
+}
+
+func interpolate(points []Point, v float64) float64 {
+	sorted := make([]Point, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Value < sorted[j].Value })
+
+	if v <= sorted[0].Value {
+		return sorted[0].Score
+	}
+	for i := 1; i < len(sorted); i++ {
+		if v <= sorted[i].Value {
+			a, b := sorted[i-1], sorted[i]
+			return a.Score + (b.Score-a.Score)*(v-a.Value)/(b.Value-a.Value)
+		}
+	}
+	return sorted[len(sorted)-1].Score
+}
+
+// Model is weighted average of components
+type Model struct {
+	Components []Component `json:"components"`
+}
+
+// LoadModel reads model from JSON file
+func LoadModel(path string) (*Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can not open model file: %w", err)
+	}
+	defer func() { f.Close() }()
+
+	var m Model
+	if err := json.NewDecoder(f).Decode(&m); err != nil {
+		return nil, fmt.Errorf("can not decode model: %w", err)
+	}
+	return &m, nil
+}
+
+// Score is health of single module
+type Score struct {
+	Total     float64
+	Breakdown map[string]float64 // component name to its score
+}
+
+// Score computes weighted average of components that can be scored.
+// Returns false if none of components can be scored.
+func (m Model) Score(fields map[string]interface{}) (Score, bool) {
+	score := Score{Breakdown: map[string]float64{}}
+	var sum, weights float64
+	for _, c := range m.Components {
+		s, ok := c.score(fields)
+		if !ok || c.Weight <= 0 {
+			continue
+		}
+		score.Breakdown[c.Name] = round(s)
+		sum += s * c.Weight
+		weights += c.Weight
+	}
+	if weights == 0 {
+		return score, false
+	}
+	score.Total = round(sum / weights)
+	return score, true
+}
+
+// Worst is lowest score among modules that are reachable from module
+type Worst struct {
+	Score  float64
+	Module string
+}
+
+// WorstTransitive finds for every module lowest score among its dependencies, direct and indirect.
+// Modules without dependencies with scores are not included.
+func WorstTransitive(g gomodgraph.Graph, scores map[string]float64) map[string]Worst {
+	worst := map[string]Worst{}
+	for _, n := range g.Modules {
+		for dep := range g.Reachable(n.ModuleName) {
+			s, ok := scores[dep]
+			if dep == n.ModuleName || !ok {
+				continue
+			}
+			if w, ok := worst[n.ModuleName]; !ok || s < w.Score || (s == w.Score && dep < w.Module) {
+				worst[n.ModuleName] = Worst{Score: s, Module: dep}
+			}
+		}
+	}
+	return worst
+}
+
+func round(v float64) float64 { return math.Round(v*10) / 10 }
+
+func ptr(v float64) *float64 { return &v }
+
+// DefaultModel is used when no model is configured
+var DefaultModel = Model{
+	Components: []Component{
+		{Name: "git_activity", Field: "git_last_commit_days_since", Weight: 2, Points: []Point{{Value: 180, Score: 100}, {Value: 730, Score: 0}}},
+		{Name: "git_contributors", Field: "git_num_contributors", Weight: 1, Points: []Point{{Value: 1, Score: 20}, {Value: 5, Score: 70}, {Value: 20, Score: 100}}},
+		{Name: "release_cadence", Field: "git_last_release_days_since", Weight: 1, Points: []Point{{Value: 365, Score: 100}, {Value: 1095, Score: 0}}, Missing: ptr(30)},
+		{Name: "tests", Field: "gotest_all_tests_passed", Weight: 1.5, Values: map[string]float64{"true": 100, "false": 0}},
+		{Name: "coverage", Field: "gotest_package_coverage_avg", Weight: 1, Points: []Point{{Value: 0, Score: 0}, {Value: 80, Score: 100}}},
+		{Name: "goreportcard", Field: "goreportcard_grade", Weight: 1, Values: map[string]float64{"A+": 100, "A": 90, "B": 75, "C": 50, "D": 30, "E": 15, "F": 0}},
+		{Name: "stars", Field: "github_repo_stars", Weight: 1, Points: []Point{{Value: 0, Score: 0}, {Value: 100, Score: 60}, {Value: 1000, Score: 100}}},
+		{Name: "vulnerabilities", Field: "osv_num_vulnerabilities", Weight: 3, Points: []Point{{Value: 0, Score: 100}, {Value: 1, Score: 30}, {Value: 3, Score: 0}}},
+		{Name: "deprecation", Field: "readme_deprecated", Weight: 3, Values: map[string]float64{"true": 0, "false": 100}, Missing: ptr(100)},
+		{Name: "license", Field: "license_category", Weight: 1, Values: map[string]float64{"permissive": 100, "weak-copyleft": 70, "strong-copyleft": 30, "network-copyleft": 0, "unknown": 20}},
+	},
+}
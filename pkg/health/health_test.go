@@ -0,0 +1,60 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+func TestModelScore(t *testing.T) {
+	model := Model{
+		Components: []Component{
+			{Name: "activity", Field: "days", Weight: 2, Points: []Point{{Value: 100, Score: 100}, {Value: 300, Score: 0}}},
+			{Name: "grade", Field: "grade", Weight: 1, Values: map[string]float64{"A": 100, "C": 40}},
+			{Name: "deprecated", Field: "deprecated", Weight: 1, Values: map[string]float64{"true": 0, "false": 100}, Missing: ptr(100)},
+		},
+	}
+
+	t.Run("all components", func(t *testing.T) {
+		s, ok := model.Score(map[string]interface{}{"days": 200.0, "grade": "C", "deprecated": true})
+		assert.True(t, ok)
+		assert.Equal(t, Score{Total: 35, Breakdown: map[string]float64{"activity": 50, "grade": 40, "deprecated": 0}}, s)
+	})
+
+	t.Run("missing components are skipped or use default", func(t *testing.T) {
+		s, ok := model.Score(map[string]interface{}{"grade": "A"})
+		assert.True(t, ok)
+		assert.Equal(t, Score{Total: 100, Breakdown: map[string]float64{"grade": 100, "deprecated": 100}}, s)
+	})
+}
+
+func TestDefaultModelVulnerabilities(t *testing.T) {
+	fields := map[string]interface{}{"goreportcard_grade": "A+", "readme_deprecated": false}
+
+	s, ok := DefaultModel.Score(fields)
+	assert.True(t, ok)
+	// goreportcard, release cadence that is missing, deprecation: (100*1 + 30*1 + 100*3) / 5
+	assert.Equal(t, 86.0, s.Total)
+	assert.NotContains(t, s.Breakdown, "vulnerabilities")
+
+	fields["osv_num_vulnerabilities"] = 2.0
+	s, ok = DefaultModel.Score(fields)
+	assert.True(t, ok)
+	assert.Equal(t, 15.0, s.Breakdown["vulnerabilities"])
+	// (100*1 + 30*1 + 15*3 + 100*3) / 8
+	assert.Equal(t, 59.4, s.Total)
+}
+
+func TestWorstTransitive(t *testing.T) {
+	g := gomodgraph.Graph{
+		Modules: []gomodgraph.Node{{ModuleName: "root"}, {ModuleName: "a"}, {ModuleName: "b"}, {ModuleName: "c"}},
+		Edges:   []gomodgraph.Edge{{From: "root", To: "a"}, {From: "root", To: "b"}, {From: "a", To: "c"}},
+	}
+	worst := WorstTransitive(g, map[string]float64{"root": 90, "a": 95, "b": 60, "c": 10})
+	assert.Equal(t, map[string]Worst{
+		"root": {Score: 10, Module: "c"},
+		"a":    {Score: 10, Module: "c"},
+	}, worst)
+}
@@ -0,0 +1,92 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+func graph(days uint, score float64) collector.Graph {
+	return collector.Graph{
+		Modules: []collector.ModuleStats{
+			{ID: "root"},
+			{
+				ID:          "a",
+				GitStats:    &collector.GitStats{DaysSinceLastCommit: days},
+				HealthStats: &collector.HealthStats{Score: score},
+			},
+		},
+		Edges: []collector.Edge{{From: "root", To: "a"}},
+	}
+}
+
+func TestStore(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	require.NoError(t, err)
+	defer func() { store.Close() }()
+
+	t1 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+	t3 := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, tm := range []time.Time{t2, t1, t3} {
+		root, err := store.Save(graph(uint(i*200), 90-float64(i)*20), tm)
+		require.NoError(t, err)
+		assert.Equal(t, "root", root)
+	}
+
+	roots, err := store.Roots()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"root"}, roots)
+
+	times, err := store.Times("root")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{t1, t2, t3}, times)
+
+	t.Run("latest", func(t *testing.T) {
+		run, ok, err := store.Latest("root", t2.Add(time.Hour))
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, t2, run.Time)
+
+		run, ok, err = store.Latest("root", time.Now())
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, t3, run.Time)
+
+		_, ok, err = store.Latest("root", t1.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("series", func(t *testing.T) {
+		runs, err := store.Runs("root", t2)
+		require.NoError(t, err)
+		series, err := Series(runs, "a", []string{"git_last_commit_days_since"})
+		require.NoError(t, err)
+		assert.Equal(t, []Point{
+			{Time: t2, Fields: map[string]interface{}{"git_last_commit_days_since": 0.0}},
+			{Time: t3, Fields: map[string]interface{}{"git_last_commit_days_since": 400.0}},
+		}, series)
+	})
+}
+
+func TestRegressionChecker(t *testing.T) {
+	old := Run{Graph: graph(100, 90)}
+	curr := Run{Graph: graph(400, 70)}
+
+	regressions, err := DefaultRegressionChecker.Check(old, curr)
+	require.NoError(t, err)
+	assert.Equal(t, []Regression{
+		{Module: "a", Field: "git_last_commit_days_since", From: 100.0, To: 400.0, Reason: "became abandoned, no commits for 400 days"},
+		{Module: "a", Field: "health_score", From: 90.0, To: 70.0, Reason: "health score dropped by 20.0"},
+	}, regressions)
+
+	regressions, err = DefaultRegressionChecker.Check(curr, old)
+	require.NoError(t, err)
+	assert.Empty(t, regressions)
+}
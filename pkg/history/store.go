@@ -0,0 +1,178 @@
+// Package history keeps results of runs in local database to track modules over time
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+var bucketRuns = []byte("runs")
+
+// timeFormat is fixed width so that keys are ordered by time
+const timeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// Store keeps runs in bbolt database.
+// Runs are in bucket per main module, keyed by time of run, values are graphs as JSONL.
+type Store struct {
+	DB *bolt.DB
+}
+
+// Open opens or creates database at path
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("can not open database: %w", err)
+	}
+	return &Store{DB: db}, nil
+}
+
+// Close closes database
+func (s *Store) Close() error { return s.DB.Close() }
+
+// Run is single stored graph
+type Run struct {
+	Root  string
+	Time  time.Time
+	Graph collector.Graph
+}
+
+// Save stores graph as run of its main module at given time
+func (s *Store) Save(g collector.Graph, t time.Time) (string, error) {
+	root := g.GoModGraph().Root()
+	if root == "" {
+		return "", fmt.Errorf("can not save empty graph")
+	}
+
+	var b bytes.Buffer
+	if err := g.WriteJSONL(&b); err != nil {
+		return root, fmt.Errorf("can not encode graph: %w", err)
+	}
+
+	err := s.DB.Update(func(tx *bolt.Tx) error {
+		runs, err := tx.CreateBucketIfNotExists(bucketRuns)
+		if err != nil {
+			return err
+		}
+		bucket, err := runs.CreateBucketIfNotExists([]byte(root))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(t.UTC().Format(timeFormat)), b.Bytes())
+	})
+	if err != nil {
+		return root, fmt.Errorf("can not save run: %w", err)
+	}
+	return root, nil
+}
+
+// Roots returns main modules that have runs, sorted
+func (s *Store) Roots() ([]string, error) {
+	var roots []string
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(bucketRuns)
+		if runs == nil {
+			return nil
+		}
+		return runs.ForEach(func(k, v []byte) error {
+			roots = append(roots, string(k))
+			return nil
+		})
+	})
+	sort.Strings(roots)
+	return roots, err
+}
+
+// Times returns times of runs of main module, oldest first
+func (s *Store) Times(root string) ([]time.Time, error) {
+	var times []time.Time
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		bucket := rootBucket(tx, root)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			t, err := time.Parse(timeFormat, string(k))
+			if err != nil {
+				return fmt.Errorf("can not parse time of run %s: %w", k, err)
+			}
+			times = append(times, t)
+			return nil
+		})
+	})
+	return times, err
+}
+
+// Runs returns all runs of main module from given time, oldest first
+func (s *Store) Runs(root string, since time.Time) ([]Run, error) {
+	var runs []Run
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		bucket := rootBucket(tx, root)
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		for k, v := c.Seek([]byte(since.UTC().Format(timeFormat))); k != nil; k, v = c.Next() {
+			run, err := decodeRun(root, k, v)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, run)
+		}
+		return nil
+	})
+	return runs, err
+}
+
+// Latest returns last run of main module that is not after given time, false if there is no such run
+func (s *Store) Latest(root string, before time.Time) (Run, bool, error) {
+	var run Run
+	var found bool
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		bucket := rootBucket(tx, root)
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		key := []byte(before.UTC().Format(timeFormat))
+		k, v := c.Seek(key)
+		if k == nil {
+			k, v = c.Last()
+		} else if bytes.Compare(k, key) > 0 {
+			k, v = c.Prev()
+		}
+		if k == nil {
+			return nil
+		}
+		var err error
+		run, err = decodeRun(root, k, v)
+		found = err == nil
+		return err
+	})
+	return run, found, err
+}
+
+func rootBucket(tx *bolt.Tx, root string) *bolt.Bucket {
+	runs := tx.Bucket(bucketRuns)
+	if runs == nil {
+		return nil
+	}
+	return runs.Bucket([]byte(root))
+}
+
+func decodeRun(root string, k, v []byte) (Run, error) {
+	t, err := time.Parse(timeFormat, string(k))
+	if err != nil {
+		return Run{}, fmt.Errorf("can not parse time of run %s: %w", k, err)
+	}
+	g, err := collector.ReadJSONL(bytes.NewReader(v))
+	if err != nil {
+		return Run{}, fmt.Errorf("can not decode run %s: %w", k, err)
+	}
+	return Run{Root: root, Time: t, Graph: g}, nil
+}
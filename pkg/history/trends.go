@@ -0,0 +1,155 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultSeriesFields are coverage, stars, staleness and score
+var DefaultSeriesFields = []string{"gotest_package_coverage_avg", "codecov_coverage", "github_repo_stars", "git_last_commit_days_since", "health_score"}
+
+// Point is values of fields of module in single run
+type Point struct {
+	Time   time.Time              `json:"time"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// Series returns values of fields of module in every run, runs without module are skipped
+func Series(runs []Run, module string, fields []string) ([]Point, error) {
+	var series []Point
+	for _, run := range runs {
+		rows, err := run.Graph.ModuleFields()
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if row["id"] != module {
+				continue
+			}
+			p := Point{Time: run.Time, Fields: map[string]interface{}{}}
+			for _, f := range fields {
+				p.Fields[f] = row[f]
+			}
+			series = append(series, p)
+		}
+	}
+	return series, nil
+}
+
+// WriteSeriesTSV writes series as table with time and fields as columns
+func WriteSeriesTSV(w io.Writer, series []Point, fields []string) error {
+	var b strings.Builder
+	b.WriteString(strings.Join(append([]string{"time"}, fields...), "\t") + "\n")
+	for _, p := range series {
+		cells := []string{p.Time.Format(time.RFC3339)}
+		for _, f := range fields {
+			if v := p.Fields[f]; v != nil {
+				cells = append(cells, fmt.Sprint(v))
+			} else {
+				cells = append(cells, "")
+			}
+		}
+		b.WriteString(strings.Join(cells, "\t") + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Regression is change of module to worse between two runs
+type Regression struct {
+	Module string      `json:"module"`
+	Field  string      `json:"field"`
+	From   interface{} `json:"from"`
+	To     interface{} `json:"to"`
+	Reason string      `json:"reason"`
+}
+
+// RegressionChecker finds modules that got worse
+type RegressionChecker struct {
+	AbandonedDays   float64 // module without commits for this many days is abandoned
+	MinScoreDrop    float64 // health score drop that is regression
+	MinCoverageDrop float64 // coverage drop in percentage points that is regression
+}
+
+// DefaultRegressionChecker is used when thresholds are not configured
+var DefaultRegressionChecker = RegressionChecker{
+	AbandonedDays:   365,
+	MinScoreDrop:    10,
+	MinCoverageDrop: 10,
+}
+
+// Check compares modules that are in both runs
+func (c RegressionChecker) Check(old, curr Run) ([]Regression, error) {
+	oldRows, err := old.Graph.ModuleFields()
+	if err != nil {
+		return nil, err
+	}
+	prev := make(map[interface{}]map[string]interface{}, len(oldRows))
+	for _, row := range oldRows {
+		prev[row["id"]] = row
+	}
+
+	newRows, err := curr.Graph.ModuleFields()
+	if err != nil {
+		return nil, err
+	}
+
+	var regressions []Regression
+	for _, curr := range newRows {
+		before, ok := prev[curr["id"]]
+		if !ok {
+			continue
+		}
+		module, _ := curr["id"].(string)
+		add := func(field, reason string) {
+			regressions = append(regressions, Regression{Module: module, Field: field, From: before[field], To: curr[field], Reason: reason})
+		}
+
+		if a, b, ok := numbers(before, curr, "git_last_commit_days_since"); ok && a < c.AbandonedDays && b >= c.AbandonedDays {
+			add("git_last_commit_days_since", fmt.Sprintf("became abandoned, no commits for %.0f days", b))
+		}
+		if before["readme_deprecated"] != true && curr["readme_deprecated"] == true {
+			add("readme_deprecated", "became deprecated")
+		}
+		if a, b, ok := numbers(before, curr, "health_score"); ok && a-b >= c.MinScoreDrop {
+			add("health_score", fmt.Sprintf("health score dropped by %.1f", a-b))
+		}
+		for _, f := range []string{"gotest_package_coverage_avg", "codecov_coverage"} {
+			if a, b, ok := numbers(before, curr, f); ok && a-b >= c.MinCoverageDrop {
+				add(f, fmt.Sprintf("coverage dropped by %.1f", a-b))
+			}
+		}
+		if before["gotest_all_tests_passed"] == true && curr["gotest_all_tests_passed"] == false {
+			add("gotest_all_tests_passed", "tests started to fail")
+		}
+		if a, b := before["license"], curr["license"]; a != nil && a != b {
+			add("license", "license changed")
+		}
+	}
+
+	sort.SliceStable(regressions, func(i, j int) bool { return regressions[i].Module < regressions[j].Module })
+	return regressions, nil
+}
+
+func numbers(a, b map[string]interface{}, field string) (float64, float64, bool) {
+	va, okA := a[field].(float64)
+	vb, okB := b[field].(float64)
+	return va, vb, okA && okB
+}
+
+// WriteRegressionsText writes regressions one per line
+func WriteRegressionsText(w io.Writer, old, curr Run, regressions []Regression) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "comparing %s to %s\n", curr.Time.Format(time.RFC3339), old.Time.Format(time.RFC3339))
+	if len(regressions) == 0 {
+		b.WriteString("no regressions\n")
+	}
+	for _, r := range regressions {
+		fmt.Fprintf(&b, "REGRESSION: %s: %s (%s: %v -> %v)\n", r.Module, r.Reason, r.Field, r.From, r.To)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,120 @@
+// Package hosting has common stats of repository on any code hosting, e.g. GitHub, GitLab, Gitea, Bitbucket, SourceHut
+package hosting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ProviderEnum is kind of code hosting
+type ProviderEnum string
+
+const (
+	ProviderGitHub    ProviderEnum = "github"
+	ProviderGitLab    ProviderEnum = "gitlab"
+	ProviderGitea     ProviderEnum = "gitea" // Forgejo has same API, e.g. codeberg.org
+	ProviderBitbucket ProviderEnum = "bitbucket"
+	ProviderSourceHut ProviderEnum = "sourcehut"
+)
+
+// Summary is stats that all hostings have, fields are missing when hosting does not have them
+type Summary struct {
+	Provider      ProviderEnum `json:"hosting_provider"`
+	URL           string       `json:"hosting_url,omitempty"`
+	NumStars      *int         `json:"hosting_stars,omitempty"`
+	NumForks      *int         `json:"hosting_forks,omitempty"`
+	NumOpenIssues *int         `json:"hosting_open_issues,omitempty"`
+	IsArchived    bool         `json:"hosting_archived"`
+	LastPushAt    *time.Time   `json:"hosting_last_push_at,omitempty"`
+	LastPushDays  *uint        `json:"hosting_last_push_days_since,omitempty"`
+	License       string       `json:"hosting_license,omitempty"` // SPDX identifier
+}
+
+// SetLastPush sets time of last push in UTC and days since it
+func (s *Summary) SetLastPush(t *time.Time, now time.Time) {
+	if t == nil {
+		return
+	}
+	u := t.UTC()
+	days := uint(now.Sub(u).Hours() / 24)
+	s.LastPushAt = &u
+	s.LastPushDays = &days
+}
+
+// Provider gets summary of repository from its hosting API
+type Provider interface {
+	GetSummary(ctx context.Context, repoURL url.URL) (*Summary, error)
+}
+
+// Providers picks provider by host of repository URL
+type Providers map[string]Provider
+
+// GetSummary gets summary of repository with provider of its host
+func (p Providers) GetSummary(ctx context.Context, repoURL url.URL) (*Summary, error) {
+	provider, ok := p[strings.ToLower(repoURL.Host)]
+	if !ok {
+		return nil, fmt.Errorf("hosting %s is not configured", repoURL.Host)
+	}
+	return provider.GetSummary(ctx, repoURL)
+}
+
+// Hosts returns configured hosts
+func (p Providers) Hosts() []string {
+	hosts := make([]string, 0, len(p))
+	for h := range p {
+		hosts = append(hosts, h)
+	}
+	return hosts
+}
+
+// Host is self-hosted instance of hosting
+type Host struct {
+	Host     string       `json:"host"` // as in module names and git URLs, e.g. git.example.com
+	Provider ProviderEnum `json:"provider"`
+	APIURL   string       `json:"api_url,omitempty"`   // default is https://<host>/api/v1 for Gitea and https://<host>/query for SourceHut
+	TokenEnv string       `json:"token_env,omitempty"` // environment variable with token, tokens are not kept in config
+}
+
+// ReadHosts reads JSON array of hosts and fills in default API URLs.
+// Only Gitea and SourceHut can be self-hosted, Bitbucket Server has different API than Bitbucket Cloud.
+func ReadHosts(r io.Reader) ([]Host, error) {
+	var hosts []Host
+	if err := json.NewDecoder(r).Decode(&hosts); err != nil {
+		return nil, fmt.Errorf("can not decode hosts: %w", err)
+	}
+	for i, h := range hosts {
+		h.Host = strings.ToLower(strings.TrimSpace(h.Host))
+		if h.Host == "" {
+			return nil, errors.New("host is empty")
+		}
+		switch h.Provider {
+		case ProviderGitea:
+			if h.APIURL == "" {
+				h.APIURL = "https://" + h.Host + "/api/v1"
+			}
+		case ProviderSourceHut:
+			if h.APIURL == "" {
+				h.APIURL = "https://" + h.Host + "/query"
+			}
+		default:
+			return nil, fmt.Errorf("%s: provider %q can not be self-hosted, GitHub Enterprise and GitLab have their own hosts", h.Host, h.Provider)
+		}
+		hosts[i] = h
+	}
+	return hosts, nil
+}
+
+// RepoPath returns owner and name of repository for hostings where repositories are not nested, e.g. codeberg.org/owner/name
+func RepoPath(repoURL url.URL) (owner, name string) {
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(repoURL.Path, ".git"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
@@ -0,0 +1,56 @@
+package hosting
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProvider struct{ summary *Summary }
+
+func (p mockProvider) GetSummary(ctx context.Context, repoURL url.URL) (*Summary, error) {
+	if p.summary == nil {
+		return nil, errors.New("not found")
+	}
+	return p.summary, nil
+}
+
+func TestProviders(t *testing.T) {
+	providers := Providers{"codeberg.org": mockProvider{summary: &Summary{Provider: ProviderGitea}}}
+
+	s, err := providers.GetSummary(context.Background(), url.URL{Host: "Codeberg.org", Path: "/a/b"})
+	require.NoError(t, err)
+	assert.Equal(t, ProviderGitea, s.Provider)
+
+	_, err = providers.GetSummary(context.Background(), url.URL{Host: "git.example.com", Path: "/a/b"})
+	assert.Error(t, err)
+}
+
+func TestReadHosts(t *testing.T) {
+	hosts, err := ReadHosts(strings.NewReader(`[
+		{"host": "Git.Example.com", "provider": "gitea", "token_env": "GITEA_TOKEN"},
+		{"host": "git.example.org", "provider": "sourcehut"}
+	]`))
+	require.NoError(t, err)
+	assert.Equal(t, []Host{
+		{Host: "git.example.com", Provider: ProviderGitea, APIURL: "https://git.example.com/api/v1", TokenEnv: "GITEA_TOKEN"},
+		{Host: "git.example.org", Provider: ProviderSourceHut, APIURL: "https://git.example.org/query"},
+	}, hosts)
+
+	_, err = ReadHosts(strings.NewReader(`[{"host": "bitbucket.example.com", "provider": "bitbucket"}]`))
+	assert.Error(t, err)
+}
+
+func TestRepoPath(t *testing.T) {
+	owner, name := RepoPath(url.URL{Host: "git.sr.ht", Path: "/~sircmpwn/scdoc"})
+	assert.Equal(t, "~sircmpwn", owner)
+	assert.Equal(t, "scdoc", name)
+
+	owner, _ = RepoPath(url.URL{Host: "codeberg.org", Path: "/a/b/c"})
+	assert.Equal(t, "", owner)
+}
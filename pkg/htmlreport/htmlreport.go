@@ -0,0 +1,67 @@
+// Package htmlreport renders collected graph as single self-contained interactive HTML file
+package htmlreport
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+//go:embed report.html
+var reportHTML string
+
+var reportTemplate = template.Must(template.New("report").Parse(reportHTML))
+
+// reportData is embedded into page as JSON
+type reportData struct {
+	Root    string                   `json:"root"`
+	Modules []map[string]interface{} `json:"modules"`
+	Edges   []collector.Edge         `json:"edges"`
+}
+
+// Writer renders graph to HTML that works offline
+type Writer struct {
+	Title string
+}
+
+// Write renders graph
+func (c Writer) Write(w io.Writer, g collector.Graph) error {
+	data := reportData{
+		Root:    g.GoModGraph().Root(),
+		Modules: []map[string]interface{}{},
+		Edges:   g.Edges,
+	}
+	if data.Edges == nil {
+		data.Edges = []collector.Edge{}
+	}
+	for _, m := range g.Modules {
+		fields, err := m.Fields()
+		if err != nil {
+			return fmt.Errorf("can not get fields of %s: %w", m.ID, err)
+		}
+		data.Modules = append(data.Modules, fields)
+	}
+
+	// json escapes <, > and & so data can not close script tag
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("can not marshal report data: %w", err)
+	}
+
+	title := c.Title
+	if title == "" {
+		title = "import-graph: " + data.Root
+	}
+
+	return reportTemplate.Execute(w, struct {
+		Title string
+		Data  template.JS
+	}{
+		Title: title,
+		Data:  template.JS(dataJSON),
+	})
+}
@@ -0,0 +1,60 @@
+package htmlreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/collector/collectortest"
+	"github.com/nikolaydubina/import-graph/pkg/golden"
+)
+
+// data returns JSON embedded into page
+func data(t *testing.T, page string) string {
+	start := strings.Index(page, "const data = ")
+	require.NotEqual(t, -1, start)
+	start += len("const data = ")
+	end := strings.Index(page[start:], ";\n")
+	require.NotEqual(t, -1, end)
+	return page[start : start+end]
+}
+
+func TestWriter(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, Writer{}.Write(&b, collectortest.Graph()))
+	page := b.String()
+
+	t.Run("title", func(t *testing.T) {
+		assert.Contains(t, page, "<title>import-graph: example.com/app</title>")
+		assert.Contains(t, page, "<h1>import-graph: example.com/app</h1>")
+	})
+
+	t.Run("data", func(t *testing.T) {
+		var indented bytes.Buffer
+		require.NoError(t, json.Indent(&indented, []byte(data(t, page)), "", "  "))
+		indented.WriteString("\n")
+		golden.Equal(t, "data.json", indented.Bytes())
+	})
+
+	t.Run("module names can not close script", func(t *testing.T) {
+		assert.Equal(t, 1, strings.Count(page, "</script>"))
+	})
+
+	t.Run("works offline", func(t *testing.T) {
+		assert.NotContains(t, page, "src=")
+		assert.NotContains(t, page, "<link")
+	})
+}
+
+func TestWriterTitle(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, Writer{Title: "deps <of> service"}.Write(&b, collector.Graph{}))
+	assert.Contains(t, b.String(), "<title>deps &lt;of&gt; service</title>")
+	assert.Contains(t, b.String(), `"modules":[]`)
+	assert.Contains(t, b.String(), `"edges":[]`)
+}
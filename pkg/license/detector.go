@@ -0,0 +1,178 @@
+// Package license detects licenses of modules and evaluates them against policy
+package license
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CategoryEnum is how restrictive license is
+type CategoryEnum string
+
+const (
+	CategoryPermissive      CategoryEnum = "permissive"
+	CategoryWeakCopyleft    CategoryEnum = "weak-copyleft"
+	CategoryStrongCopyleft  CategoryEnum = "strong-copyleft"
+	CategoryNetworkCopyleft CategoryEnum = "network-copyleft"
+	CategoryUnknown         CategoryEnum = "unknown"
+)
+
+// rank orders categories from least to most restrictive
+func (c CategoryEnum) rank() int {
+	switch c {
+	case CategoryPermissive:
+		return 0
+	case CategoryWeakCopyleft:
+		return 1
+	case CategoryStrongCopyleft:
+		return 2
+	case CategoryNetworkCopyleft:
+		return 3
+	default:
+		return -1
+	}
+}
+
+// signature is set of phrases that all have to be present in license text
+type signature struct {
+	SPDXID   string
+	Phrases  []string
+	Category CategoryEnum
+}
+
+// signatures are checked in order, more specific ones go first.
+// GNU licenses refer to each other, e.g. GPL-3.0 has section about AGPL-3.0, so they are matched by title and then by notice.
+var signatures = []signature{
+	{SPDXID: "AGPL-3.0", Phrases: []string{"gnu affero general public license version 3"}, Category: CategoryNetworkCopyleft},
+	{SPDXID: "LGPL-3.0", Phrases: []string{"gnu lesser general public license version 3"}, Category: CategoryWeakCopyleft},
+	{SPDXID: "LGPL-2.1", Phrases: []string{"gnu lesser general public license version 2.1"}, Category: CategoryWeakCopyleft},
+	{SPDXID: "GPL-3.0", Phrases: []string{"gnu general public license version 3"}, Category: CategoryStrongCopyleft},
+	{SPDXID: "GPL-2.0", Phrases: []string{"gnu general public license version 2"}, Category: CategoryStrongCopyleft},
+	{SPDXID: "AGPL-3.0", Phrases: []string{"gnu affero general public license as published by the free software foundation", "version 3"}, Category: CategoryNetworkCopyleft},
+	{SPDXID: "LGPL-3.0", Phrases: []string{"gnu lesser general public license as published by the free software foundation", "version 3"}, Category: CategoryWeakCopyleft},
+	{SPDXID: "LGPL-2.1", Phrases: []string{"gnu lesser general public license as published by the free software foundation", "version 2.1"}, Category: CategoryWeakCopyleft},
+	{SPDXID: "GPL-3.0", Phrases: []string{"gnu general public license as published by the free software foundation", "version 3"}, Category: CategoryStrongCopyleft},
+	{SPDXID: "GPL-2.0", Phrases: []string{"gnu general public license as published by the free software foundation", "version 2"}, Category: CategoryStrongCopyleft},
+	{SPDXID: "MPL-2.0", Phrases: []string{"mozilla public license", "2.0"}, Category: CategoryWeakCopyleft},
+	{SPDXID: "EPL-2.0", Phrases: []string{"eclipse public license", "2.0"}, Category: CategoryWeakCopyleft},
+	{SPDXID: "EPL-1.0", Phrases: []string{"eclipse public license"}, Category: CategoryWeakCopyleft},
+	{SPDXID: "Apache-2.0", Phrases: []string{"apache license", "version 2.0"}, Category: CategoryPermissive},
+	{SPDXID: "BSL-1.0", Phrases: []string{"boost software license"}, Category: CategoryPermissive},
+	{SPDXID: "Unlicense", Phrases: []string{"free and unencumbered software released into the public domain"}, Category: CategoryPermissive},
+	{SPDXID: "CC0-1.0", Phrases: []string{"cc0 1.0 universal"}, Category: CategoryPermissive},
+	{SPDXID: "ISC", Phrases: []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}, Category: CategoryPermissive},
+	{SPDXID: "MIT", Phrases: []string{"permission is hereby granted, free of charge"}, Category: CategoryPermissive},
+	{SPDXID: "BSD-3-Clause", Phrases: []string{"redistribution and use in source and binary forms", "neither the name"}, Category: CategoryPermissive},
+	{SPDXID: "BSD-2-Clause", Phrases: []string{"redistribution and use in source and binary forms"}, Category: CategoryPermissive},
+}
+
+// Category returns category of SPDX license identifier or expression.
+// For expressions with OR the least restrictive alternative is taken, since it can be chosen.
+func Category(spdxExpr string) CategoryEnum {
+	best := CategoryUnknown
+	for _, id := range splitOr(spdxExpr) {
+		for _, s := range signatures {
+			if strings.EqualFold(s.SPDXID, normalizeID(id)) && (best == CategoryUnknown || s.Category.rank() < best.rank()) {
+				best = s.Category
+			}
+		}
+	}
+	return best
+}
+
+// KnownID returns SPDX identifier of known license in canonical case, e.g. apache-2.0 becomes Apache-2.0, or empty string if not known.
+// Version qualifiers are kept, e.g. gpl-3.0-or-later becomes GPL-3.0-or-later.
+func KnownID(id string) string {
+	for _, suffix := range []string{"-only", "-or-later", "+"} {
+		if len(id) > len(suffix) && strings.EqualFold(id[len(id)-len(suffix):], suffix) {
+			if known := knownID(id[:len(id)-len(suffix)]); known != "" {
+				return known + suffix
+			}
+			return ""
+		}
+	}
+	return knownID(id)
+}
+
+func knownID(id string) string {
+	for _, s := range signatures {
+		if strings.EqualFold(s.SPDXID, id) {
+			return s.SPDXID
+		}
+	}
+	return ""
+}
+
+// IdentifyText returns SPDX identifier of license text or empty string if not known
+func IdentifyText(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	for _, s := range signatures {
+		matched := true
+		for _, p := range s.Phrases {
+			if !strings.Contains(normalized, p) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return s.SPDXID
+		}
+	}
+	return ""
+}
+
+var licenseFileName = regexp.MustCompile(`(?i)^(un)?(licen[sc]e|copying)([.-].*)?$`)
+
+// LocalLicenseDetector detects license from files in root of cloned repository
+type LocalLicenseDetector struct{}
+
+// Detect returns SPDX expression for license files at path.
+// Multiple different licenses are joined with OR, which is how dual licensing is usually done.
+func (c LocalLicenseDetector) Detect(path string) string {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return ""
+	}
+
+	found := map[string]bool{}
+	for _, f := range files {
+		if f.IsDir() || !licenseFileName.MatchString(f.Name()) {
+			continue
+		}
+		text, err := ioutil.ReadFile(filepath.Join(path, f.Name()))
+		if err != nil {
+			continue
+		}
+		if id := IdentifyText(string(text)); id != "" {
+			found[id] = true
+		}
+	}
+
+	ids := make([]string, 0, len(found))
+	for id := range found {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, " OR ")
+}
+
+func splitOr(spdxExpr string) []string {
+	var ids []string
+	for _, id := range strings.Split(strings.Trim(spdxExpr, "() "), " OR ") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// normalizeID drops version qualifiers, e.g. GPL-3.0-or-later becomes GPL-3.0
+func normalizeID(id string) string {
+	for _, suffix := range []string{"-only", "-or-later", "+"} {
+		id = strings.TrimSuffix(id, suffix)
+	}
+	return id
+}
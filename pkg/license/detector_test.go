@@ -0,0 +1,79 @@
+package license
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentifyText(t *testing.T) {
+	// full texts of GNU licenses, they refer to each other
+	for _, id := range []string{"AGPL-3.0", "GPL-3.0", "GPL-2.0", "LGPL-3.0", "LGPL-2.1"} {
+		t.Run(id, func(t *testing.T) {
+			text, err := ioutil.ReadFile(filepath.Join("testdata", id))
+			require.NoError(t, err)
+			assert.Equal(t, id, IdentifyText(string(text)))
+		})
+	}
+
+	tests := []struct {
+		name string
+		text string
+		id   string
+	}{
+		{
+			name: "AGPL-3.0 notice",
+			text: `This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.`,
+			id: "AGPL-3.0",
+		},
+		{
+			name: "GPL-3.0 notice",
+			text: `This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.`,
+			id: "GPL-3.0",
+		},
+		{
+			name: "LGPL-3.0 title",
+			text: "GNU LESSER GENERAL PUBLIC LICENSE\n   Version 3, 29 June 2007",
+			id:   "LGPL-3.0",
+		},
+		{
+			name: "MIT",
+			text: "Permission is hereby granted, free of charge,\n to any person",
+			id:   "MIT",
+		},
+		{
+			name: "unknown",
+			text: "all rights reserved",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.id, IdentifyText(tc.text))
+		})
+	}
+}
+
+func TestLocalLicenseDetector(t *testing.T) {
+	dir, err := ioutil.TempDir("", "license")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gpl, err := ioutil.ReadFile(filepath.Join("testdata", "GPL-3.0"))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "COPYING"), gpl, 0644))
+	assert.Equal(t, "GPL-3.0", LocalLicenseDetector{}.Detect(dir))
+
+	mit := []byte("MIT License\n\nPermission is hereby granted, free of charge, to any person obtaining a copy")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "LICENSE-MIT"), mit, 0644))
+	assert.Equal(t, "GPL-3.0 OR MIT", LocalLicenseDetector{}.Detect(dir))
+}
@@ -0,0 +1,192 @@
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// DecisionEnum is outcome of evaluating license against policy
+type DecisionEnum string
+
+const (
+	DecisionAllowed   DecisionEnum = "allowed"
+	DecisionReview    DecisionEnum = "review"
+	DecisionForbidden DecisionEnum = "forbidden"
+)
+
+// rank orders decisions from best to worst
+func (d DecisionEnum) rank() int {
+	switch d {
+	case DecisionAllowed:
+		return 0
+	case DecisionReview:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Policy lists which SPDX identifiers are allowed, require review or are forbidden
+type Policy struct {
+	ProjectLicense string       `json:"project_license,omitempty"` // e.g. "MIT", used for compatibility checks
+	Allowed        []string     `json:"allowed"`
+	Review         []string     `json:"review"`
+	Forbidden      []string     `json:"forbidden"`
+	Unlisted       DecisionEnum `json:"unlisted,omitempty"` // license detected but not in any list, default is review
+	Unknown        DecisionEnum `json:"unknown,omitempty"`  // license can not be detected, default is review
+}
+
+// LoadPolicy reads policy from JSON file
+func LoadPolicy(path string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can not open policy file: %w", err)
+	}
+	defer func() { f.Close() }()
+
+	var p Policy
+	if err := json.NewDecoder(f).Decode(&p); err != nil {
+		return nil, fmt.Errorf("can not decode policy: %w", err)
+	}
+	return &p, nil
+}
+
+// Finding is evaluation of license of single module
+type Finding struct {
+	Module   string
+	License  string
+	Decision DecisionEnum
+	Reason   string
+	Via      []string // direct dependencies of root through which module is pulled in
+}
+
+// Report is result of evaluating all modules in graph
+type Report struct {
+	Findings []Finding // only findings that are not allowed
+}
+
+// HasForbidden is true if any module has forbidden license
+func (r Report) HasForbidden() bool {
+	for _, f := range r.Findings {
+		if f.Decision == DecisionForbidden {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes human readable report
+func (r Report) WriteText(w io.Writer) error {
+	if len(r.Findings) == 0 {
+		_, err := fmt.Fprintln(w, "all licenses are allowed")
+		return err
+	}
+	for _, f := range r.Findings {
+		license := f.License
+		if license == "" {
+			license = "unknown license"
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s (%s): %s\n", strings.ToUpper(string(f.Decision)), f.Module, license, f.Reason); err != nil {
+			return err
+		}
+		if len(f.Via) > 0 {
+			if _, err := fmt.Fprintf(w, "\tpulled in by: %s\n", strings.Join(f.Via, ", ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate checks license of every module except root.
+// licenses maps module name to SPDX expression, empty or missing expression means license is not known.
+func (p Policy) Evaluate(g gomodgraph.Graph, licenses map[string]string) Report {
+	var report Report
+	root := g.Root()
+	for _, n := range g.Modules {
+		if n.ModuleName == root {
+			continue
+		}
+		decision, reason := p.decide(licenses[n.ModuleName])
+		if decision == DecisionAllowed {
+			continue
+		}
+		report.Findings = append(report.Findings, Finding{
+			Module:   n.ModuleName,
+			License:  licenses[n.ModuleName],
+			Decision: decision,
+			Reason:   reason,
+			Via:      g.DirectDependenciesReaching(n.ModuleName),
+		})
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Decision.rank() > report.Findings[j].Decision.rank()
+	})
+	return report
+}
+
+// decide picks best decision among OR alternatives of SPDX expression
+func (p Policy) decide(spdxExpr string) (DecisionEnum, string) {
+	ids := splitOr(spdxExpr)
+	if len(ids) == 0 {
+		return orDefault(p.Unknown), "license is not detected"
+	}
+
+	var best DecisionEnum
+	var bestReason string
+	for _, id := range ids {
+		decision, reason := p.decideID(id)
+		if best == "" || decision.rank() < best.rank() {
+			best, bestReason = decision, reason
+		}
+	}
+	return best, bestReason
+}
+
+func (p Policy) decideID(id string) (DecisionEnum, string) {
+	if p.ProjectLicense != "" && !IsCompatible(p.ProjectLicense, id) {
+		return DecisionForbidden, fmt.Sprintf("not compatible with project license %s", p.ProjectLicense)
+	}
+	switch {
+	case containsID(p.Forbidden, id):
+		return DecisionForbidden, "forbidden by policy"
+	case containsID(p.Review, id):
+		return DecisionReview, "requires review by policy"
+	case containsID(p.Allowed, id):
+		return DecisionAllowed, ""
+	default:
+		return orDefault(p.Unlisted), "not listed in policy"
+	}
+}
+
+// IsCompatible checks if dependency license can be used by project with given license.
+// Permissive and weak copyleft can be used by anyone, strong copyleft requires project to be at least as restrictive.
+func IsCompatible(projectLicense, depLicense string) bool {
+	dep := Category(depLicense)
+	if dep == CategoryUnknown || dep.rank() <= CategoryWeakCopyleft.rank() {
+		return true
+	}
+	return Category(projectLicense).rank() >= dep.rank()
+}
+
+func containsID(ids []string, id string) bool {
+	for _, v := range ids {
+		if strings.EqualFold(normalizeID(v), normalizeID(id)) {
+			return true
+		}
+	}
+	return false
+}
+
+func orDefault(d DecisionEnum) DecisionEnum {
+	if d == "" {
+		return DecisionReview
+	}
+	return d
+}
@@ -0,0 +1,62 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	g := gomodgraph.Graph{
+		Modules: []gomodgraph.Node{{ModuleName: "root"}, {ModuleName: "a"}, {ModuleName: "b"}, {ModuleName: "c"}, {ModuleName: "d"}},
+		Edges: []gomodgraph.Edge{
+			{From: "root", To: "a"},
+			{From: "root", To: "b"},
+			{From: "a", To: "c"},
+			{From: "b", To: "d"},
+		},
+	}
+	policy := Policy{
+		Allowed:   []string{"MIT", "Apache-2.0"},
+		Review:    []string{"MPL-2.0"},
+		Forbidden: []string{"AGPL-3.0"},
+	}
+
+	t.Run("forbidden license is reported with direct dependency", func(t *testing.T) {
+		report := policy.Evaluate(g, map[string]string{"a": "MIT", "b": "Apache-2.0", "c": "AGPL-3.0", "d": "MPL-2.0"})
+		assert.True(t, report.HasForbidden())
+		assert.Equal(t, []Finding{
+			{Module: "c", License: "AGPL-3.0", Decision: DecisionForbidden, Reason: "forbidden by policy", Via: []string{"a"}},
+			{Module: "d", License: "MPL-2.0", Decision: DecisionReview, Reason: "requires review by policy", Via: []string{"b"}},
+		}, report.Findings)
+	})
+
+	t.Run("dual license picks best alternative", func(t *testing.T) {
+		report := policy.Evaluate(g, map[string]string{"a": "MIT", "b": "MIT", "c": "AGPL-3.0 OR MIT", "d": "MIT"})
+		assert.False(t, report.HasForbidden())
+		assert.Empty(t, report.Findings)
+	})
+
+	t.Run("unknown license requires review", func(t *testing.T) {
+		report := policy.Evaluate(g, map[string]string{"a": "MIT", "b": "MIT", "c": "MIT"})
+		assert.Equal(t, []Finding{{Module: "d", Decision: DecisionReview, Reason: "license is not detected", Via: []string{"b"}}}, report.Findings)
+	})
+
+	t.Run("strong copyleft is not compatible with permissive project", func(t *testing.T) {
+		p := Policy{ProjectLicense: "MIT", Allowed: []string{"MIT", "GPL-3.0"}}
+		report := p.Evaluate(g, map[string]string{"a": "MIT", "b": "MIT", "c": "GPL-3.0-only", "d": "MIT"})
+		assert.True(t, report.HasForbidden())
+		assert.Equal(t, "not compatible with project license MIT", report.Findings[0].Reason)
+	})
+}
+
+func TestKnownID(t *testing.T) {
+	assert.Equal(t, "Apache-2.0", KnownID("apache-2.0"))
+	assert.Equal(t, "MIT", KnownID("mit"))
+	assert.Equal(t, "", KnownID("other"))
+	assert.Equal(t, "GPL-3.0-or-later", KnownID("gpl-3.0-or-later"))
+	assert.Equal(t, "LGPL-2.1-only", KnownID("LGPL-2.1-only"))
+	assert.Equal(t, "", KnownID("other-or-later"))
+}
@@ -0,0 +1,162 @@
+// Package osv finds known vulnerabilities of module versions in OSV database, which includes Go vulnerability database
+package osv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Module is module at version
+type Module struct {
+	Name    string
+	Version string // e.g. v1.2.3
+}
+
+// Client is client of OSV API
+type Client struct {
+	HTTPClient *http.Client
+	APIURL     string // e.g. https://api.osv.dev/v1
+}
+
+// batchSize is max number of queries in single request that OSV API accepts
+const batchSize = 1000
+
+type query struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Version string `json:"version"`
+}
+
+type batchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+type vulnerability struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases"`
+}
+
+// GetVulnerabilities returns IDs of known vulnerabilities of every module, modules without vulnerabilities have empty list.
+// Same vulnerability can be in several databases, e.g. GO-2022-0969 and GHSA-69cg-p879-7622, then it is listed once, by Go ID.
+func (c Client) GetVulnerabilities(ctx context.Context, modules []Module) (map[Module][]string, error) {
+	found := make(map[Module][]string, len(modules))
+	for start := 0; start < len(modules); start += batchSize {
+		end := start + batchSize
+		if end > len(modules) {
+			end = len(modules)
+		}
+		if err := c.queryBatch(ctx, modules[start:end], found); err != nil {
+			return nil, err
+		}
+	}
+
+	aliases := map[string][]string{}
+	for m, ids := range found {
+		for _, id := range ids {
+			if _, ok := aliases[id]; ok {
+				continue
+			}
+			var v vulnerability
+			if err := c.do(ctx, http.MethodGet, "/vulns/"+url.PathEscape(id), nil, &v); err != nil {
+				return nil, fmt.Errorf("can not get vulnerability %s: %w", id, err)
+			}
+			aliases[id] = v.Aliases
+		}
+		found[m] = dedupe(ids, aliases)
+	}
+	return found, nil
+}
+
+func (c Client) queryBatch(ctx context.Context, modules []Module, found map[Module][]string) error {
+	req := struct {
+		Queries []query `json:"queries"`
+	}{Queries: make([]query, len(modules))}
+	for i, m := range modules {
+		req.Queries[i].Package.Name = m.Name
+		req.Queries[i].Package.Ecosystem = "Go"
+		// OSV has Go versions without v prefix
+		req.Queries[i].Version = strings.TrimPrefix(m.Version, "v")
+	}
+
+	var resp batchResponse
+	if err := c.do(ctx, http.MethodPost, "/querybatch", req, &resp); err != nil {
+		return fmt.Errorf("can not query vulnerabilities: %w", err)
+	}
+	if len(resp.Results) != len(modules) {
+		return fmt.Errorf("got %d results for %d modules", len(resp.Results), len(modules))
+	}
+	for i, r := range resp.Results {
+		ids := []string{}
+		for _, v := range r.Vulns {
+			ids = append(ids, v.ID)
+		}
+		found[modules[i]] = ids
+	}
+	return nil
+}
+
+// dedupe keeps single ID of every vulnerability, Go IDs go first since their aliases include other databases
+func dedupe(ids []string, aliases map[string][]string) []string {
+	sorted := make([]string, len(ids))
+	copy(sorted, ids)
+	sort.Slice(sorted, func(i, j int) bool {
+		gi, gj := strings.HasPrefix(sorted[i], "GO-"), strings.HasPrefix(sorted[j], "GO-")
+		if gi != gj {
+			return gi
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	seen := map[string]bool{}
+	kept := []string{}
+	for _, id := range sorted {
+		if seen[id] {
+			continue
+		}
+		kept = append(kept, id)
+		seen[id] = true
+		for _, a := range aliases[id] {
+			seen[a] = true
+		}
+	}
+	return kept
+}
+
+// do makes request to API with JSON body, if it is not nil, and decodes JSON response into v
+func (c Client) do(ctx context.Context, method, path string, body, v interface{}) error {
+	var b bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&b).Encode(body); err != nil {
+			return fmt.Errorf("can not encode request: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.APIURL, "/")+path, &b)
+	if err != nil {
+		return fmt.Errorf("can not make request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("can not make request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("can not decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package osv
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVulnerabilities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/querybatch":
+			var req struct {
+				Queries []query `json:"queries"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Len(t, req.Queries, 2)
+			assert.Equal(t, "golang.org/x/net", req.Queries[0].Package.Name)
+			assert.Equal(t, "Go", req.Queries[0].Package.Ecosystem)
+			assert.Equal(t, "0.0.0-20220722155237-a158d28d115b", req.Queries[0].Version)
+
+			b, err := ioutil.ReadFile("testdata/querybatch_response.json")
+			require.NoError(t, err)
+			w.Write(b)
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/vulns/"):
+			b, err := ioutil.ReadFile(filepath.Join("testdata", strings.TrimPrefix(r.URL.Path, "/v1/vulns/")+".json"))
+			if err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(b)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	net := Module{Name: "golang.org/x/net", Version: "v0.0.0-20220722155237-a158d28d115b"}
+	yaml := Module{Name: "gopkg.in/yaml.v3", Version: "v3.0.1"}
+
+	client := Client{HTTPClient: server.Client(), APIURL: server.URL + "/v1"}
+	vulns, err := client.GetVulnerabilities(context.Background(), []Module{net, yaml})
+	require.NoError(t, err)
+	assert.Equal(t, map[Module][]string{
+		net:  {"GO-2022-0969", "GO-2023-1571"},
+		yaml: {},
+	}, vulns)
+}
+
+func TestGetVulnerabilitiesError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := Client{HTTPClient: server.Client(), APIURL: server.URL}
+	_, err := client.GetVulnerabilities(context.Background(), []Module{{Name: "a", Version: "v1.0.0"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,421 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr is compiled boolean expression over fields of single module.
+// Values are same as in JSONL: numbers, strings, booleans and null for missing fields.
+type Expr struct {
+	source string
+	root   node
+}
+
+// ParseExpr compiles expression.
+// Supports field names, literals, comparison (== != < <= > >=), membership (in [...]), logical (&& || !) and parentheses.
+// Example: git_last_commit_days_since > 730 || goreportcard_grade in ["C", "D", "E", "F"]
+func ParseExpr(source string) (*Expr, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected token %q", p.peek().text)
+	}
+	return &Expr{source: source, root: root}, nil
+}
+
+// Eval returns true if expression holds for fields
+func (e *Expr) Eval(fields map[string]interface{}) bool {
+	return truthy(e.root.eval(fields))
+}
+
+// String returns source of expression
+func (e *Expr) String() string { return e.source }
+
+// Fields returns names of all fields referenced in expression
+func (e *Expr) Fields() []string {
+	tokens, _ := tokenize(e.source)
+	var names []string
+	seen := map[string]bool{}
+	for _, t := range tokens {
+		if t.kind == tokenIdent && !isKeyword(t.text) && !seen[t.text] {
+			seen[t.text] = true
+			names = append(names, t.text)
+		}
+	}
+	return names
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ","}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(source) && source[end] != '"' {
+				if source[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(source) {
+				return nil, errors.New("unterminated string")
+			}
+			s, err := strconv.Unquote(source[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("bad string %s: %w", source[i:end+1], err)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s})
+			i = end + 1
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(source) && unicode.IsDigit(rune(source[i+1]))):
+			end := i + 1
+			for end < len(source) && (unicode.IsDigit(rune(source[end])) || source[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[i:end]})
+			i = end
+		case unicode.IsLetter(c) || c == '_':
+			end := i + 1
+			for end < len(source) && (unicode.IsLetter(rune(source[end])) || unicode.IsDigit(rune(source[end])) || source[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[i:end]})
+			i = end
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(source[i:], op) {
+					tokens = append(tokens, token{kind: tokenOp, text: op})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+			}
+		}
+	}
+	return tokens, nil
+}
+
+func isKeyword(s string) bool {
+	return s == "true" || s == "false" || s == "null" || s == "in"
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) isOp(text string) bool {
+	t := p.peek()
+	return !p.done() && (t.kind == tokenOp || t.kind == tokenIdent) && t.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.isOp(text) {
+		return fmt.Errorf("expected %q", text)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&") {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.isOp("!") {
+		p.pos++
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner: inner}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.isOp(op) {
+			p.pos++
+			right, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			return compareNode{op: op, left: left, right: right}, nil
+		}
+	}
+	if p.isOp("in") {
+		p.pos++
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return inNode{value: left, list: list}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseList() ([]interface{}, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	var list []interface{}
+	for !p.isOp("]") {
+		if len(list) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		item, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		lit, ok := item.(literalNode)
+		if !ok {
+			return nil, errors.New("list can contain only literals")
+		}
+		list = append(list, lit.value)
+	}
+	return list, p.expect("]")
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.done() {
+		return nil, errors.New("unexpected end of expression")
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokenOp && t.text == "(":
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case t.kind == tokenNumber:
+		p.pos++
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %s: %w", t.text, err)
+		}
+		return literalNode{value: v}, nil
+	case t.kind == tokenString:
+		p.pos++
+		return literalNode{value: t.text}, nil
+	case t.kind == tokenIdent && t.text == "true":
+		p.pos++
+		return literalNode{value: true}, nil
+	case t.kind == tokenIdent && t.text == "false":
+		p.pos++
+		return literalNode{value: false}, nil
+	case t.kind == tokenIdent && t.text == "null":
+		p.pos++
+		return literalNode{value: nil}, nil
+	case t.kind == tokenIdent && t.text != "in":
+		p.pos++
+		return fieldNode{name: t.text}, nil
+	default:
+		return nil, fmt.Errorf("unexpected token %q", t.text)
+	}
+}
+
+type node interface {
+	eval(fields map[string]interface{}) interface{}
+}
+
+type literalNode struct{ value interface{} }
+
+func (n literalNode) eval(map[string]interface{}) interface{} { return n.value }
+
+type fieldNode struct{ name string }
+
+func (n fieldNode) eval(fields map[string]interface{}) interface{} { return fields[n.name] }
+
+type notNode struct{ inner node }
+
+func (n notNode) eval(fields map[string]interface{}) interface{} {
+	return !truthy(n.inner.eval(fields))
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(fields map[string]interface{}) interface{} {
+	return truthy(n.left.eval(fields)) && truthy(n.right.eval(fields))
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(fields map[string]interface{}) interface{} {
+	return truthy(n.left.eval(fields)) || truthy(n.right.eval(fields))
+}
+
+type inNode struct {
+	value node
+	list  []interface{}
+}
+
+func (n inNode) eval(fields map[string]interface{}) interface{} {
+	v := n.value.eval(fields)
+	for _, item := range n.list {
+		if equal(v, item) {
+			return true
+		}
+	}
+	return false
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+// eval compares values of same type, ordering of values of different types or null is always false
+func (n compareNode) eval(fields map[string]interface{}) interface{} {
+	a, b := n.left.eval(fields), n.right.eval(fields)
+	switch n.op {
+	case "==":
+		return equal(a, b)
+	case "!=":
+		return !equal(a, b)
+	}
+
+	var cmp int
+	switch av := a.(type) {
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return false
+		}
+		switch {
+		case av < bv:
+			cmp = -1
+		case av > bv:
+			cmp = 1
+		}
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return false
+		}
+		cmp = strings.Compare(av, bv)
+	default:
+		return false
+	}
+
+	switch n.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func equal(a, b interface{}) bool {
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case float64:
+		bv, ok := b.(float64)
+		return ok && av == bv
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	default:
+		return false
+	}
+}
+
+// truthy follows JSON semantics, missing fields are false
+func truthy(v interface{}) bool {
+	switch vv := v.(type) {
+	case bool:
+		return vv
+	case float64:
+		return vv != 0
+	case string:
+		return vv != ""
+	default:
+		return false
+	}
+}
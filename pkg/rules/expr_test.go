@@ -0,0 +1,48 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExprEval(t *testing.T) {
+	fields := map[string]interface{}{
+		"git_last_commit_days_since": 800.0,
+		"goreportcard_grade":         "C",
+		"readme_deprecated":          true,
+		"files_has_tests":            false,
+	}
+
+	tests := []struct {
+		expr string
+		exp  bool
+	}{
+		{expr: "git_last_commit_days_since > 730", exp: true},
+		{expr: "git_last_commit_days_since <= 730", exp: false},
+		{expr: "readme_deprecated", exp: true},
+		{expr: "!files_has_tests && readme_deprecated", exp: true},
+		{expr: `goreportcard_grade in ["C", "D", "E", "F"]`, exp: true},
+		{expr: `goreportcard_grade == "A+" || (github_repo_stars < 10)`, exp: false},
+		{expr: "github_repo_stars == null", exp: true},
+		{expr: "github_repo_stars < 10", exp: false},
+		{expr: "files_has_tests == false", exp: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			e, err := ParseExpr(tc.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, e.Eval(fields))
+		})
+	}
+}
+
+func TestParseExprError(t *testing.T) {
+	for _, expr := range []string{"", "a >", "(a == 1", "a in [b]", `a == "x`, "a # b"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseExpr(expr)
+			assert.Error(t, err)
+		})
+	}
+}
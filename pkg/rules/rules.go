@@ -0,0 +1,279 @@
+// Package rules evaluates declarative rules against collected module fields
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// ScopeEnum is which dependencies rule applies to
+type ScopeEnum string
+
+const (
+	ScopeAll        ScopeEnum = "all"
+	ScopeDirect     ScopeEnum = "direct"
+	ScopeTransitive ScopeEnum = "transitive"
+)
+
+// SeverityEnum is how violation affects exit code
+type SeverityEnum string
+
+const (
+	SeverityError   SeverityEnum = "error"
+	SeverityWarning SeverityEnum = "warning"
+)
+
+// Rule is violated when expression is true for module
+type Rule struct {
+	Name     string       `json:"name"`
+	When     string       `json:"when"` // expression, e.g. "git_last_commit_days_since > 730"
+	Message  string       `json:"message,omitempty"`
+	Scope    ScopeEnum    `json:"scope,omitempty"`    // default is all
+	Severity SeverityEnum `json:"severity,omitempty"` // default is error
+
+	expr *Expr
+}
+
+// Exemption disables rules for module until it expires
+type Exemption struct {
+	Module  string   `json:"module"`            // module name, or prefix with "/..." suffix
+	Rules   []string `json:"rules,omitempty"`   // empty means all rules
+	Expires string   `json:"expires,omitempty"` // date as 2006-01-02, empty means never
+	Reason  string   `json:"reason,omitempty"`
+}
+
+// Config is rules file
+type Config struct {
+	Rules      []Rule      `json:"rules"`
+	Exemptions []Exemption `json:"exemptions,omitempty"`
+}
+
+// LoadConfig reads rules from JSON file and compiles expressions
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can not open rules file: %w", err)
+	}
+	defer func() { f.Close() }()
+
+	var c Config
+	if err := json.NewDecoder(f).Decode(&c); err != nil {
+		return nil, fmt.Errorf("can not decode rules: %w", err)
+	}
+	if err := c.Compile(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Compile parses expressions of all rules and validates exemptions
+func (c *Config) Compile() error {
+	for i, r := range c.Rules {
+		expr, err := ParseExpr(r.When)
+		if err != nil {
+			return fmt.Errorf("can not parse rule %s: %w", r.Name, err)
+		}
+		c.Rules[i].expr = expr
+	}
+	for _, e := range c.Exemptions {
+		if e.Expires == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", e.Expires); err != nil {
+			return fmt.Errorf("bad expiry date for exemption of %s: %w", e.Module, err)
+		}
+	}
+	return nil
+}
+
+// Violation is single rule violated by single module
+type Violation struct {
+	Module   string
+	Rule     string
+	Message  string
+	Severity SeverityEnum
+	Direct   bool
+	Values   map[string]interface{} // values of fields referenced in rule
+}
+
+// Report is result of evaluating rules against graph
+type Report struct {
+	Violations        []Violation
+	Exempted          []Violation
+	ExpiredExemptions []Exemption // exemptions that matched violation but are expired
+}
+
+// HasErrors is true if there are violations with error severity
+func (r Report) HasErrors() bool {
+	for _, v := range r.Violations {
+		if v.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes human readable report
+func (r Report) WriteText(w io.Writer) error {
+	for _, v := range r.Violations {
+		kind := "transitive"
+		if v.Direct {
+			kind = "direct"
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s (%s): %s: %s%s\n", strings.ToUpper(string(v.Severity)), v.Module, kind, v.Rule, v.Message, formatValues(v.Values)); err != nil {
+			return err
+		}
+	}
+	for _, e := range r.ExpiredExemptions {
+		if _, err := fmt.Fprintf(w, "EXPIRED EXEMPTION: %s %v expired on %s\n", e.Module, e.Rules, e.Expires); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d violations, %d exempted\n", len(r.Violations), len(r.Exempted))
+	return err
+}
+
+// Evaluate checks every module except root against all rules.
+// fields maps module name to its fields as in JSONL.
+// Config that is not compiled yet is compiled here, caller keeps it as is.
+func (c Config) Evaluate(g gomodgraph.Graph, fields map[string]map[string]interface{}, now time.Time) (Report, error) {
+	if !c.isCompiled() {
+		c.Rules = append([]Rule(nil), c.Rules...)
+		if err := c.Compile(); err != nil {
+			return Report{}, err
+		}
+	}
+
+	var report Report
+	root := g.Root()
+	direct := map[string]bool{}
+	for _, d := range g.Children(root) {
+		direct[d] = true
+	}
+	expiredSeen := map[int]bool{}
+
+	for _, n := range g.Modules {
+		if n.ModuleName == root {
+			continue
+		}
+		for _, r := range c.Rules {
+			if !r.inScope(direct[n.ModuleName]) || !r.expr.Eval(fields[n.ModuleName]) {
+				continue
+			}
+
+			v := Violation{
+				Module:   n.ModuleName,
+				Rule:     r.Name,
+				Message:  r.Message,
+				Severity: r.Severity,
+				Direct:   direct[n.ModuleName],
+				Values:   map[string]interface{}{},
+			}
+			if v.Severity == "" {
+				v.Severity = SeverityError
+			}
+			if v.Message == "" {
+				v.Message = r.When
+			}
+			for _, name := range r.expr.Fields() {
+				v.Values[name] = fields[n.ModuleName][name]
+			}
+
+			exempted := false
+			for i, e := range c.Exemptions {
+				if !e.matches(n.ModuleName, r.Name) {
+					continue
+				}
+				if e.isExpired(now) {
+					if !expiredSeen[i] {
+						report.ExpiredExemptions = append(report.ExpiredExemptions, e)
+						expiredSeen[i] = true
+					}
+					continue
+				}
+				exempted = true
+			}
+
+			if exempted {
+				report.Exempted = append(report.Exempted, v)
+			} else {
+				report.Violations = append(report.Violations, v)
+			}
+		}
+	}
+	return report, nil
+}
+
+func (c Config) isCompiled() bool {
+	for _, r := range c.Rules {
+		if r.expr == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Rule) inScope(isDirect bool) bool {
+	switch r.Scope {
+	case ScopeDirect:
+		return isDirect
+	case ScopeTransitive:
+		return !isDirect
+	default:
+		return true
+	}
+}
+
+func (e Exemption) matches(module string, rule string) bool {
+	if prefix := strings.TrimSuffix(e.Module, "/..."); prefix != e.Module {
+		if module != prefix && !strings.HasPrefix(module, prefix+"/") {
+			return false
+		}
+	} else if module != e.Module {
+		return false
+	}
+	if len(e.Rules) == 0 {
+		return true
+	}
+	for _, r := range e.Rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// isExpired is true when current day is after expiry day
+func (e Exemption) isExpired(now time.Time) bool {
+	if e.Expires == "" {
+		return false
+	}
+	expires, err := time.Parse("2006-01-02", e.Expires)
+	if err != nil {
+		return true
+	}
+	return now.After(expires.AddDate(0, 0, 1))
+}
+
+func formatValues(values map[string]interface{}) string {
+	if len(values) == 0 {
+		return ""
+	}
+	var parts []string
+	for k, v := range values {
+		if v == nil {
+			parts = append(parts, k+"=null")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(parts)
+	return " (" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,215 @@
+package rules
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// root -> github.com/a/x -> github.com/b/y
+// root -> github.com/c/z
+func testGraph() gomodgraph.Graph {
+	return gomodgraph.Graph{
+		Modules: []gomodgraph.Node{{ModuleName: "root"}, {ModuleName: "github.com/a/x"}, {ModuleName: "github.com/b/y"}, {ModuleName: "github.com/c/z"}},
+		Edges: []gomodgraph.Edge{
+			{From: "root", To: "github.com/a/x"},
+			{From: "root", To: "github.com/c/z"},
+			{From: "github.com/a/x", To: "github.com/b/y"},
+		},
+	}
+}
+
+// every module, root too, is stale
+var testFields = map[string]map[string]interface{}{
+	"root":           {"git_last_commit_days_since": 900.0},
+	"github.com/a/x": {"git_last_commit_days_since": 800.0},
+	"github.com/b/y": {"git_last_commit_days_since": 1000.0, "readme_deprecated": true},
+	"github.com/c/z": {"git_last_commit_days_since": 750.0},
+}
+
+var now = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// violated returns modules of violations by rule, in order
+func violated(violations []Violation) map[string][]string {
+	modules := map[string][]string{}
+	for _, v := range violations {
+		modules[v.Rule] = append(modules[v.Rule], v.Module)
+	}
+	return modules
+}
+
+func TestEvaluate(t *testing.T) {
+	stale := Rule{Name: "stale", When: "git_last_commit_days_since > 730"}
+	deprecated := Rule{Name: "deprecated", When: "readme_deprecated", Severity: SeverityWarning}
+
+	tests := []struct {
+		name       string
+		config     Config
+		violations map[string][]string
+		exempted   map[string][]string
+		expired    []Exemption
+		hasErrors  bool
+	}{
+		{
+			name:       "root is not checked",
+			config:     Config{Rules: []Rule{stale, deprecated}},
+			violations: map[string][]string{"stale": {"github.com/a/x", "github.com/b/y", "github.com/c/z"}, "deprecated": {"github.com/b/y"}},
+			exempted:   map[string][]string{},
+			hasErrors:  true,
+		},
+		{
+			name:       "warnings are not errors",
+			config:     Config{Rules: []Rule{deprecated}},
+			violations: map[string][]string{"deprecated": {"github.com/b/y"}},
+			exempted:   map[string][]string{},
+		},
+		{
+			name:       "direct scope",
+			config:     Config{Rules: []Rule{{Name: "stale", When: stale.When, Scope: ScopeDirect}}},
+			violations: map[string][]string{"stale": {"github.com/a/x", "github.com/c/z"}},
+			exempted:   map[string][]string{},
+			hasErrors:  true,
+		},
+		{
+			name:       "transitive scope",
+			config:     Config{Rules: []Rule{{Name: "stale", When: stale.When, Scope: ScopeTransitive}}},
+			violations: map[string][]string{"stale": {"github.com/b/y"}},
+			exempted:   map[string][]string{},
+			hasErrors:  true,
+		},
+		{
+			name: "exemption of module for all rules",
+			config: Config{
+				Rules:      []Rule{stale, deprecated},
+				Exemptions: []Exemption{{Module: "github.com/b/y"}},
+			},
+			violations: map[string][]string{"stale": {"github.com/a/x", "github.com/c/z"}},
+			exempted:   map[string][]string{"stale": {"github.com/b/y"}, "deprecated": {"github.com/b/y"}},
+			hasErrors:  true,
+		},
+		{
+			name: "exemption of single rule",
+			config: Config{
+				Rules:      []Rule{stale, deprecated},
+				Exemptions: []Exemption{{Module: "github.com/b/y", Rules: []string{"deprecated"}}},
+			},
+			violations: map[string][]string{"stale": {"github.com/a/x", "github.com/b/y", "github.com/c/z"}},
+			exempted:   map[string][]string{"deprecated": {"github.com/b/y"}},
+			hasErrors:  true,
+		},
+		{
+			name: "exemption by prefix",
+			config: Config{
+				Rules:      []Rule{stale},
+				Exemptions: []Exemption{{Module: "github.com/a/..."}, {Module: "github.com/c"}},
+			},
+			violations: map[string][]string{"stale": {"github.com/b/y", "github.com/c/z"}},
+			exempted:   map[string][]string{"stale": {"github.com/a/x"}},
+			hasErrors:  true,
+		},
+		{
+			name: "exemption is valid on day of expiry",
+			config: Config{
+				Rules:      []Rule{deprecated},
+				Exemptions: []Exemption{{Module: "github.com/b/y", Expires: "2024-03-10"}},
+			},
+			violations: map[string][]string{},
+			exempted:   map[string][]string{"deprecated": {"github.com/b/y"}},
+		},
+		{
+			name: "expired exemption is reported once",
+			config: Config{
+				Rules:      []Rule{stale, deprecated},
+				Exemptions: []Exemption{{Module: "github.com/b/y", Expires: "2024-03-09"}},
+			},
+			violations: map[string][]string{"stale": {"github.com/a/x", "github.com/b/y", "github.com/c/z"}, "deprecated": {"github.com/b/y"}},
+			exempted:   map[string][]string{},
+			expired:    []Exemption{{Module: "github.com/b/y", Expires: "2024-03-09"}},
+			hasErrors:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.config.Compile())
+			report, err := tc.config.Evaluate(testGraph(), testFields, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.violations, violated(report.Violations))
+			assert.Equal(t, tc.exempted, violated(report.Exempted))
+			assert.Equal(t, tc.expired, report.ExpiredExemptions)
+			assert.Equal(t, tc.hasErrors, report.HasErrors())
+		})
+	}
+}
+
+func TestEvaluateViolation(t *testing.T) {
+	config := Config{Rules: []Rule{
+		{Name: "stale", When: "git_last_commit_days_since > 730 && github_repo_stars < 10"},
+		{Name: "deprecated", When: "readme_deprecated", Message: "module is deprecated", Severity: SeverityWarning, Scope: ScopeTransitive},
+	}}
+	require.NoError(t, config.Compile())
+
+	g := testGraph()
+	fields := map[string]map[string]interface{}{
+		"github.com/a/x": {"git_last_commit_days_since": 800.0, "github_repo_stars": 3.0},
+		"github.com/b/y": {"readme_deprecated": true},
+	}
+	report, err := config.Evaluate(g, fields, now)
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{
+		{
+			Module:   "github.com/a/x",
+			Rule:     "stale",
+			Message:  "git_last_commit_days_since > 730 && github_repo_stars < 10",
+			Severity: SeverityError,
+			Direct:   true,
+			Values:   map[string]interface{}{"git_last_commit_days_since": 800.0, "github_repo_stars": 3.0},
+		},
+		{
+			Module:   "github.com/b/y",
+			Rule:     "deprecated",
+			Message:  "module is deprecated",
+			Severity: SeverityWarning,
+			Values:   map[string]interface{}{"readme_deprecated": true},
+		},
+	}, report.Violations)
+
+	var b bytes.Buffer
+	require.NoError(t, report.WriteText(&b))
+	assert.Equal(t, `ERROR: github.com/a/x (direct): stale: git_last_commit_days_since > 730 && github_repo_stars < 10 (git_last_commit_days_since=800, github_repo_stars=3)
+WARNING: github.com/b/y (transitive): deprecated: module is deprecated (readme_deprecated=true)
+2 violations, 0 exempted
+`, b.String())
+}
+
+func TestEvaluateNotCompiled(t *testing.T) {
+	t.Run("compiles rules", func(t *testing.T) {
+		config := Config{Rules: []Rule{{Name: "deprecated", When: "readme_deprecated"}}}
+		report, err := config.Evaluate(testGraph(), testFields, now)
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{"deprecated": {"github.com/b/y"}}, violated(report.Violations))
+		assert.Nil(t, config.Rules[0].expr)
+	})
+
+	t.Run("bad expression", func(t *testing.T) {
+		config := Config{Rules: []Rule{{Name: "bad", When: "readme_deprecated &&"}}}
+		_, err := config.Evaluate(testGraph(), testFields, now)
+		assert.Error(t, err)
+	})
+}
+
+func TestCompileErrors(t *testing.T) {
+	t.Run("bad expression", func(t *testing.T) {
+		c := Config{Rules: []Rule{{Name: "bad", When: "git_last_commit_days_since >"}}}
+		assert.Error(t, c.Compile())
+	})
+
+	t.Run("bad expiry date", func(t *testing.T) {
+		c := Config{Exemptions: []Exemption{{Module: "a", Expires: "10.03.2024"}}}
+		assert.Error(t, c.Compile())
+	})
+}
@@ -0,0 +1,183 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+// CycloneDX is CycloneDX 1.5 JSON document, only fields used by import-graph
+type CycloneDX struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber,omitempty"`
+	Version      int                   `json:"version"`
+	Metadata     *CycloneDXMetadata    `json:"metadata,omitempty"`
+	Components   []CycloneDXComponent  `json:"components,omitempty"`
+	Dependencies []CycloneDXDependency `json:"dependencies,omitempty"`
+}
+
+// CycloneDXMetadata describes document and component it is about
+type CycloneDXMetadata struct {
+	Timestamp string              `json:"timestamp,omitempty"`
+	Tools     *CycloneDXTools     `json:"tools,omitempty"`
+	Component *CycloneDXComponent `json:"component,omitempty"`
+}
+
+// CycloneDXTools lists tools that created document
+type CycloneDXTools struct {
+	Components []CycloneDXComponent `json:"components,omitempty"`
+}
+
+// CycloneDXComponent is single module
+type CycloneDXComponent struct {
+	Type               string                       `json:"type"`
+	BOMRef             string                       `json:"bom-ref,omitempty"`
+	Name               string                       `json:"name"`
+	Version            string                       `json:"version,omitempty"`
+	PURL               string                       `json:"purl,omitempty"`
+	Licenses           []CycloneDXLicenseChoice     `json:"licenses,omitempty"`
+	ExternalReferences []CycloneDXExternalReference `json:"externalReferences,omitempty"`
+	Properties         []CycloneDXProperty          `json:"properties,omitempty"`
+}
+
+// CycloneDXLicenseChoice is either single license or SPDX expression
+type CycloneDXLicenseChoice struct {
+	License    *CycloneDXLicense `json:"license,omitempty"`
+	Expression string            `json:"expression,omitempty"`
+}
+
+// CycloneDXLicense is single license
+type CycloneDXLicense struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// CycloneDXExternalReference is link to resource of component
+type CycloneDXExternalReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// CycloneDXProperty is name-value pair
+type CycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CycloneDXDependency lists direct dependencies of component
+type CycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// CycloneDXWriter writes graph as CycloneDX JSON.
+// Collected stats are component properties with "import-graph:" prefix.
+type CycloneDXWriter struct {
+	Timestamp time.Time
+}
+
+// Write renders graph
+func (c CycloneDXWriter) Write(w io.Writer, g collector.Graph) error {
+	serial, err := newUUID()
+	if err != nil {
+		return err
+	}
+
+	doc := CycloneDX{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + serial,
+		Version:      1,
+		Metadata: &CycloneDXMetadata{
+			Timestamp: c.Timestamp.UTC().Format(time.RFC3339),
+			Tools:     &CycloneDXTools{Components: []CycloneDXComponent{{Type: "application", Name: toolName}}},
+		},
+	}
+
+	root := g.GoModGraph().Root()
+	refs := map[string]string{}
+	for _, m := range g.Modules {
+		component, err := newCycloneDXComponent(m)
+		if err != nil {
+			return err
+		}
+		refs[m.ID] = component.BOMRef
+		if m.ID == root {
+			component.Type = "application"
+			doc.Metadata.Component = &component
+			continue
+		}
+		doc.Components = append(doc.Components, component)
+	}
+
+	dependsOn := map[string][]string{}
+	for _, e := range g.Edges {
+		if ref, ok := refs[e.To]; ok {
+			dependsOn[e.From] = append(dependsOn[e.From], ref)
+		}
+	}
+	for _, m := range g.Modules {
+		deps := dependsOn[m.ID]
+		if deps == nil {
+			deps = []string{}
+		}
+		doc.Dependencies = append(doc.Dependencies, CycloneDXDependency{Ref: refs[m.ID], DependsOn: deps})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("can not encode cyclonedx: %w", err)
+	}
+	return nil
+}
+
+func newCycloneDXComponent(m collector.ModuleStats) (CycloneDXComponent, error) {
+	purl := PackageURL(m.ID, m.Version)
+	component := CycloneDXComponent{
+		Type:    "library",
+		BOMRef:  purl,
+		Name:    m.ID,
+		Version: m.Version,
+		PURL:    purl,
+	}
+
+	if license := licenseOf(m); license != "" {
+		if strings.Contains(license, " ") {
+			component.Licenses = []CycloneDXLicenseChoice{{Expression: license}}
+		} else {
+			component.Licenses = []CycloneDXLicenseChoice{{License: &CycloneDXLicense{ID: license}}}
+		}
+	}
+	if m.GitURL != "" {
+		component.ExternalReferences = append(component.ExternalReferences, CycloneDXExternalReference{Type: "vcs", URL: m.GitURL})
+	}
+	if m.GitHubURL != "" && m.GitHubURL != m.GitURL {
+		component.ExternalReferences = append(component.ExternalReferences, CycloneDXExternalReference{Type: "website", URL: m.GitHubURL})
+	}
+
+	names, fields, err := statsFields(m)
+	if err != nil {
+		return component, err
+	}
+	for _, name := range names {
+		if fields[name] == nil {
+			continue
+		}
+		component.Properties = append(component.Properties, CycloneDXProperty{Name: toolName + ":" + name, Value: formatValue(fields[name])})
+	}
+	return component, nil
+}
+
+func formatValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,77 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector/collectortest"
+)
+
+var testTimestamp = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func TestCycloneDXWriter(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, CycloneDXWriter{Timestamp: testTimestamp}.Write(&b, collectortest.Graph()))
+
+	var doc CycloneDX
+	require.NoError(t, json.Unmarshal(b.Bytes(), &doc))
+
+	assert.Equal(t, "CycloneDX", doc.BOMFormat)
+	assert.Equal(t, "1.5", doc.SpecVersion)
+	assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, doc.SerialNumber)
+	require.NotNil(t, doc.Metadata)
+	assert.Equal(t, "2024-03-10T12:00:00Z", doc.Metadata.Timestamp)
+	require.NotNil(t, doc.Metadata.Component)
+	assert.Equal(t, "application", doc.Metadata.Component.Type)
+	assert.Equal(t, "pkg:golang/example.com/app", doc.Metadata.Component.BOMRef)
+
+	require.Len(t, doc.Components, 3)
+	x, y, z := doc.Components[0], doc.Components[1], doc.Components[2]
+	zRef := PackageURL(collectortest.ScriptModule, "v0.3.0")
+
+	t.Run("licenses", func(t *testing.T) {
+		assert.Nil(t, doc.Metadata.Component.Licenses)
+		assert.Equal(t, []CycloneDXLicenseChoice{{License: &CycloneDXLicense{ID: "MIT"}}}, x.Licenses)
+		assert.Equal(t, []CycloneDXLicenseChoice{{License: &CycloneDXLicense{ID: "AGPL-3.0"}}}, y.Licenses)
+		assert.Equal(t, []CycloneDXLicenseChoice{{Expression: "Apache-2.0 OR MIT"}}, z.Licenses)
+	})
+
+	t.Run("external references", func(t *testing.T) {
+		assert.Equal(t, []CycloneDXExternalReference{{Type: "vcs", URL: "https://github.com/a/x"}}, x.ExternalReferences)
+		assert.Equal(t, []CycloneDXExternalReference{
+			{Type: "vcs", URL: "https://github.com/a/y.git"},
+			{Type: "website", URL: "https://github.com/a/y"},
+		}, y.ExternalReferences)
+		assert.Nil(t, z.ExternalReferences)
+	})
+
+	t.Run("properties", func(t *testing.T) {
+		properties := map[string]string{}
+		for _, p := range x.Properties {
+			properties[p.Name] = p.Value
+		}
+		assert.Equal(t, "true", properties["import-graph:can_get_github"])
+		assert.Equal(t, "12", properties["import-graph:git_num_contributors"])
+		assert.Equal(t, "80", properties["import-graph:gotest_package_coverage_avg"])
+		assert.Equal(t, "90", properties["import-graph:health_score"])
+		assert.Equal(t, `{"git_activity":100,"tests":100}`, properties["import-graph:health_score_breakdown"])
+		assert.Equal(t, "MIT", properties["import-graph:license"])
+		assert.Equal(t, "permissive", properties["import-graph:license_category"])
+		assert.NotContains(t, properties, "import-graph:id")
+		assert.NotContains(t, properties, "import-graph:version")
+	})
+
+	t.Run("dependencies", func(t *testing.T) {
+		assert.Equal(t, []CycloneDXDependency{
+			{Ref: "pkg:golang/example.com/app", DependsOn: []string{"pkg:golang/github.com/a/x@v1.2.0", zRef}},
+			{Ref: "pkg:golang/github.com/a/x@v1.2.0", DependsOn: []string{"pkg:golang/github.com/a/y@v0.1.0"}},
+			{Ref: "pkg:golang/github.com/a/y@v0.1.0", DependsOn: []string{}},
+			{Ref: zRef, DependsOn: []string{}},
+		}, doc.Dependencies)
+	})
+}
@@ -0,0 +1,205 @@
+package sbom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// Parser builds module graph from CycloneDX or SPDX JSON document.
+// Only Go modules are kept, which are components with pkg:golang purl, and main component.
+type Parser struct{}
+
+// Parse detects format of document and builds graph
+func (c Parser) Parse(input io.Reader) (gomodgraph.Graph, error) {
+	b, err := ioutil.ReadAll(input)
+	if err != nil {
+		return gomodgraph.Graph{}, fmt.Errorf("can not read sbom: %w", err)
+	}
+
+	var header struct {
+		BOMFormat   string `json:"bomFormat"`
+		SPDXVersion string `json:"spdxVersion"`
+	}
+	if err := json.Unmarshal(b, &header); err != nil {
+		return gomodgraph.Graph{}, fmt.Errorf("can not unmarshal sbom: %w", err)
+	}
+
+	switch {
+	case header.BOMFormat == "CycloneDX":
+		var doc CycloneDX
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return gomodgraph.Graph{}, fmt.Errorf("can not unmarshal cyclonedx: %w", err)
+		}
+		return graphFromCycloneDX(doc), nil
+	case strings.HasPrefix(header.SPDXVersion, "SPDX-"):
+		var doc SPDX
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return gomodgraph.Graph{}, fmt.Errorf("can not unmarshal spdx: %w", err)
+		}
+		return graphFromSPDX(doc), nil
+	default:
+		return gomodgraph.Graph{}, errors.New("unknown sbom format, expected CycloneDX or SPDX JSON")
+	}
+}
+
+// graphBuilder deduplicates modules and edges, keeps highest version of module.
+// Modules are added by document specific references.
+type graphBuilder struct {
+	graph     gomodgraph.Graph
+	nodeByRef map[string]string
+	nodeIdx   map[string]int
+	edgeAdded map[gomodgraph.Edge]bool
+}
+
+func newGraphBuilder() *graphBuilder {
+	return &graphBuilder{
+		nodeByRef: map[string]string{},
+		nodeIdx:   map[string]int{},
+		edgeAdded: map[gomodgraph.Edge]bool{},
+	}
+}
+
+func (b *graphBuilder) addNode(ref string, n gomodgraph.Node) {
+	b.nodeByRef[ref] = n.ModuleName
+	idx, ok := b.nodeIdx[n.ModuleName]
+	if !ok {
+		b.graph.Modules = append(b.graph.Modules, n)
+		b.nodeIdx[n.ModuleName] = len(b.graph.Modules) - 1
+		return
+	}
+	if gomodgraph.CompareVersions(n.Version, b.graph.Modules[idx].Version) > 0 {
+		b.graph.Modules[idx].Version = n.Version
+	}
+}
+
+func (b *graphBuilder) addEdge(fromRef, toRef string) {
+	from, okFrom := b.nodeByRef[fromRef]
+	to, okTo := b.nodeByRef[toRef]
+	e := gomodgraph.Edge{From: from, To: to}
+	if !okFrom || !okTo || from == to || b.edgeAdded[e] {
+		return
+	}
+	b.graph.Edges = append(b.graph.Edges, e)
+	b.edgeAdded[e] = true
+}
+
+func graphFromCycloneDX(doc CycloneDX) gomodgraph.Graph {
+	b := newGraphBuilder()
+
+	// main component goes first, so it is root of graph
+	if doc.Metadata != nil && doc.Metadata.Component != nil {
+		root := doc.Metadata.Component
+		n, ok := ParsePackageURL(root.PURL)
+		if !ok {
+			n = gomodgraph.Node{ModuleName: root.Name, Version: root.Version}
+		}
+		b.addNode(cycloneDXRef(*root), n)
+	}
+
+	for _, component := range doc.Components {
+		if n, ok := ParsePackageURL(component.PURL); ok {
+			b.addNode(cycloneDXRef(component), n)
+		}
+	}
+
+	for _, d := range doc.Dependencies {
+		for _, to := range d.DependsOn {
+			b.addEdge(d.Ref, to)
+		}
+	}
+	return b.graph
+}
+
+// cycloneDXRef is bom-ref of component, which is optional, so purl or name@version when it is missing
+func cycloneDXRef(c CycloneDXComponent) string {
+	switch {
+	case c.BOMRef != "":
+		return c.BOMRef
+	case c.PURL != "":
+		return c.PURL
+	default:
+		return c.Name + "@" + c.Version
+	}
+}
+
+func graphFromSPDX(doc SPDX) gomodgraph.Graph {
+	b := newGraphBuilder()
+
+	roots := map[string]bool{}
+	for _, id := range doc.DocumentDescribes {
+		roots[id] = true
+	}
+	for _, r := range doc.Relationships {
+		if r.SPDXElementID == doc.SPDXID && r.RelationshipType == "DESCRIBES" {
+			roots[r.RelatedSPDXElement] = true
+		}
+	}
+
+	// described packages go first, so they are roots of graph
+	for _, onlyRoots := range []bool{true, false} {
+		for _, p := range doc.Packages {
+			if roots[p.SPDXID] != onlyRoots {
+				continue
+			}
+			n, ok := gomodgraph.Node{}, false
+			for _, ref := range p.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					if n, ok = ParsePackageURL(ref.ReferenceLocator); ok {
+						break
+					}
+				}
+			}
+			if !ok && onlyRoots {
+				n, ok = gomodgraph.Node{ModuleName: p.Name, Version: p.VersionInfo}, true
+			}
+			if ok {
+				b.addNode(p.SPDXID, n)
+			}
+		}
+	}
+
+	for _, r := range doc.Relationships {
+		switch r.RelationshipType {
+		case "DEPENDS_ON", "CONTAINS":
+			b.addEdge(r.SPDXElementID, r.RelatedSPDXElement)
+		case "DEPENDENCY_OF", "CONTAINED_BY":
+			b.addEdge(r.RelatedSPDXElement, r.SPDXElementID)
+		}
+	}
+	return b.graph
+}
+
+// ParsePackageURL extracts Go module and version from pkg:golang purl
+func ParsePackageURL(purl string) (gomodgraph.Node, bool) {
+	if !strings.HasPrefix(purl, "pkg:golang/") {
+		return gomodgraph.Node{}, false
+	}
+	purl = strings.TrimPrefix(purl, "pkg:golang/")
+	if i := strings.IndexAny(purl, "?#"); i >= 0 {
+		purl = purl[:i]
+	}
+
+	var version string
+	if i := strings.LastIndex(purl, "@"); i >= 0 {
+		purl, version = purl[:i], purl[i+1:]
+	}
+	version, err := url.PathUnescape(version)
+	if err != nil {
+		return gomodgraph.Node{}, false
+	}
+
+	segments := strings.Split(purl, "/")
+	for i, s := range segments {
+		if segments[i], err = url.PathUnescape(s); err != nil {
+			return gomodgraph.Node{}, false
+		}
+	}
+	return gomodgraph.Node{ModuleName: strings.Join(segments, "/"), Version: version}, true
+}
@@ -0,0 +1,88 @@
+package sbom
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+func TestParserRoundTrip(t *testing.T) {
+	g := collector.Graph{
+		Modules: []collector.ModuleStats{
+			{ID: "example.com/app"},
+			{ID: "github.com/gin-gonic/gin", Version: "v1.7.2"},
+			{ID: "github.com/Masterminds/semver", Version: "v3.0.0+incompatible"},
+		},
+		Edges: []collector.Edge{
+			{From: "example.com/app", To: "github.com/gin-gonic/gin"},
+			{From: "github.com/gin-gonic/gin", To: "github.com/Masterminds/semver"},
+		},
+	}
+	exp := gomodgraph.Graph{
+		Modules: []gomodgraph.Node{
+			{ModuleName: "example.com/app"},
+			{ModuleName: "github.com/gin-gonic/gin", Version: "v1.7.2"},
+			{ModuleName: "github.com/Masterminds/semver", Version: "v3.0.0+incompatible"},
+		},
+		Edges: []gomodgraph.Edge{
+			{From: "example.com/app", To: "github.com/gin-gonic/gin"},
+			{From: "github.com/gin-gonic/gin", To: "github.com/Masterminds/semver"},
+		},
+	}
+
+	t.Run("cyclonedx", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, CycloneDXWriter{Timestamp: time.Now()}.Write(&b, g))
+		parsed, err := Parser{}.Parse(&b)
+		require.NoError(t, err)
+		assert.Equal(t, exp, parsed)
+	})
+
+	t.Run("spdx", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, SPDXWriter{Timestamp: time.Now()}.Write(&b, g))
+		parsed, err := Parser{}.Parse(&b)
+		require.NoError(t, err)
+		assert.Equal(t, exp, parsed)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := Parser{}.Parse(bytes.NewBufferString(`{"foo": 1}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestParserCycloneDXWithoutBOMRef(t *testing.T) {
+	doc := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "metadata": {"component": {"type": "application", "name": "example.com/app"}},
+  "components": [
+    {"type": "library", "name": "github.com/gin-gonic/gin", "purl": "pkg:golang/github.com/gin-gonic/gin@v1.7.2"},
+    {"type": "library", "name": "github.com/Masterminds/semver", "purl": "pkg:golang/github.com/Masterminds/semver@v3.0.0%2Bincompatible"}
+  ],
+  "dependencies": [
+    {"ref": "example.com/app@", "dependsOn": ["pkg:golang/github.com/gin-gonic/gin@v1.7.2"]},
+    {"ref": "pkg:golang/github.com/gin-gonic/gin@v1.7.2", "dependsOn": ["pkg:golang/github.com/Masterminds/semver@v3.0.0%2Bincompatible"]}
+  ]
+}`
+	parsed, err := Parser{}.Parse(bytes.NewBufferString(doc))
+	require.NoError(t, err)
+	assert.Equal(t, gomodgraph.Graph{
+		Modules: []gomodgraph.Node{
+			{ModuleName: "example.com/app"},
+			{ModuleName: "github.com/gin-gonic/gin", Version: "v1.7.2"},
+			{ModuleName: "github.com/Masterminds/semver", Version: "v3.0.0+incompatible"},
+		},
+		Edges: []gomodgraph.Edge{
+			{From: "example.com/app", To: "github.com/gin-gonic/gin"},
+			{From: "github.com/gin-gonic/gin", To: "github.com/Masterminds/semver"},
+		},
+	}, parsed)
+}
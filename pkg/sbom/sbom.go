@@ -0,0 +1,56 @@
+// Package sbom writes collected graph as software bill of materials in CycloneDX and SPDX formats
+package sbom
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+// toolName is how import-graph identifies itself in documents
+const toolName = "import-graph"
+
+// PackageURL returns purl of Go module, e.g. pkg:golang/github.com/gin-gonic/gin@v1.7.2
+func PackageURL(moduleName, version string) string {
+	var segments []string
+	for _, s := range strings.Split(moduleName, "/") {
+		segments = append(segments, url.PathEscape(s))
+	}
+	purl := "pkg:golang/" + strings.Join(segments, "/")
+	if version != "" {
+		purl += "@" + strings.ReplaceAll(url.PathEscape(version), "+", "%2B")
+	}
+	return purl
+}
+
+// newUUID returns random UUID version 4
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("can not generate uuid: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// statsFields are all collected fields except those that are part of SBOM itself
+func statsFields(m collector.ModuleStats) ([]string, map[string]interface{}, error) {
+	fields, err := m.Fields()
+	if err != nil {
+		return nil, nil, err
+	}
+	delete(fields, "id")
+	delete(fields, "version")
+	return collector.PresentColumns([]map[string]interface{}{fields}), fields, nil
+}
+
+func licenseOf(m collector.ModuleStats) string {
+	if m.LicenseStats == nil {
+		return ""
+	}
+	return m.LicenseStats.License
+}
@@ -0,0 +1,13 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageURL(t *testing.T) {
+	assert.Equal(t, "pkg:golang/github.com/gin-gonic/gin@v1.7.2", PackageURL("github.com/gin-gonic/gin", "v1.7.2"))
+	assert.Equal(t, "pkg:golang/github.com/Masterminds/semver@v3.0.0%2Bincompatible", PackageURL("github.com/Masterminds/semver", "v3.0.0+incompatible"))
+	assert.Equal(t, "pkg:golang/example.com/main", PackageURL("example.com/main", ""))
+}
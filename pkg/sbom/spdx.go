@@ -0,0 +1,164 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+// SPDX is SPDX 2.3 JSON document, only fields used by import-graph
+type SPDX struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo   `json:"creationInfo"`
+	DocumentDescribes []string           `json:"documentDescribes,omitempty"`
+	Packages          []SPDXPackage      `json:"packages"`
+	Relationships     []SPDXRelationship `json:"relationships,omitempty"`
+}
+
+// SPDXCreationInfo is when and by whom document was created
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+// SPDXPackage is single module
+type SPDXPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded,omitempty"`
+	LicenseDeclared  string            `json:"licenseDeclared,omitempty"`
+	CopyrightText    string            `json:"copyrightText,omitempty"`
+	ExternalRefs     []SPDXExternalRef `json:"externalRefs,omitempty"`
+	Annotations      []SPDXAnnotation  `json:"annotations,omitempty"`
+}
+
+// SPDXExternalRef is reference to package in other system, e.g. purl
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// SPDXAnnotation is comment about package
+type SPDXAnnotation struct {
+	AnnotationType string `json:"annotationType"`
+	Annotator      string `json:"annotator"`
+	AnnotationDate string `json:"annotationDate"`
+	Comment        string `json:"comment"`
+}
+
+// SPDXRelationship is relationship between elements, e.g. DEPENDS_ON
+type SPDXRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+const spdxNoAssertion = "NOASSERTION"
+
+var spdxIDInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// SPDXWriter writes graph as SPDX 2.3 JSON.
+// Collected stats are package annotation with JSON of all fields.
+type SPDXWriter struct {
+	Timestamp time.Time
+}
+
+// Write renders graph
+func (c SPDXWriter) Write(w io.Writer, g collector.Graph) error {
+	id, err := newUUID()
+	if err != nil {
+		return err
+	}
+	created := c.Timestamp.UTC().Format(time.RFC3339)
+	root := g.GoModGraph().Root()
+
+	doc := SPDX{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              root,
+		DocumentNamespace: "https://spdx.org/spdxdocs/" + toolName + "/" + id,
+		CreationInfo:      SPDXCreationInfo{Created: created, Creators: []string{"Tool: " + toolName}},
+	}
+
+	ids := map[string]string{}
+	for i, m := range g.Modules {
+		ids[m.ID] = "SPDXRef-Package-" + spdxIDInvalidChars.ReplaceAllString(m.ID, "-") + "-" + strconv.Itoa(i)
+
+		license := licenseOf(m)
+		if license == "" {
+			license = spdxNoAssertion
+		}
+		download := m.GitURL
+		if download == "" {
+			download = spdxNoAssertion
+		}
+
+		pkg := SPDXPackage{
+			Name:             m.ID,
+			SPDXID:           ids[m.ID],
+			VersionInfo:      m.Version,
+			DownloadLocation: download,
+			FilesAnalyzed:    false,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  license,
+			CopyrightText:    spdxNoAssertion,
+			ExternalRefs: []SPDXExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  PackageURL(m.ID, m.Version),
+			}},
+		}
+
+		names, fields, err := statsFields(m)
+		if err != nil {
+			return err
+		}
+		if len(names) > 0 {
+			stats, err := json.Marshal(fields)
+			if err != nil {
+				return fmt.Errorf("can not marshal stats: %w", err)
+			}
+			pkg.Annotations = append(pkg.Annotations, SPDXAnnotation{
+				AnnotationType: "OTHER",
+				Annotator:      "Tool: " + toolName,
+				AnnotationDate: created,
+				Comment:        toolName + ": " + string(stats),
+			})
+		}
+
+		doc.Packages = append(doc.Packages, pkg)
+	}
+
+	if rootID, ok := ids[root]; ok {
+		doc.DocumentDescribes = []string{rootID}
+		doc.Relationships = append(doc.Relationships, SPDXRelationship{SPDXElementID: doc.SPDXID, RelationshipType: "DESCRIBES", RelatedSPDXElement: rootID})
+	}
+	for _, e := range g.Edges {
+		from, okFrom := ids[e.From]
+		to, okTo := ids[e.To]
+		if okFrom && okTo {
+			doc.Relationships = append(doc.Relationships, SPDXRelationship{SPDXElementID: from, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: to})
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("can not encode spdx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector/collectortest"
+)
+
+func TestSPDXWriter(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, SPDXWriter{Timestamp: testTimestamp}.Write(&b, collectortest.Graph()))
+
+	var doc SPDX
+	require.NoError(t, json.Unmarshal(b.Bytes(), &doc))
+
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Equal(t, "example.com/app", doc.Name)
+	assert.True(t, strings.HasPrefix(doc.DocumentNamespace, "https://spdx.org/spdxdocs/import-graph/"))
+	assert.Equal(t, SPDXCreationInfo{Created: "2024-03-10T12:00:00Z", Creators: []string{"Tool: import-graph"}}, doc.CreationInfo)
+
+	require.Len(t, doc.Packages, 4)
+	app, x, y, z := doc.Packages[0], doc.Packages[1], doc.Packages[2], doc.Packages[3]
+	assert.Equal(t, "SPDXRef-Package-example.com-app-0", app.SPDXID)
+	assert.Equal(t, "SPDXRef-Package-github.com-a-x-1", x.SPDXID)
+	assert.Equal(t, "SPDXRef-Package-github.com-a-y-2", y.SPDXID)
+	assert.Equal(t, "SPDXRef-Package-example.com-script-script-alert-z-script--3", z.SPDXID)
+
+	t.Run("packages", func(t *testing.T) {
+		assert.Equal(t, "NOASSERTION", app.LicenseDeclared)
+		assert.Equal(t, "NOASSERTION", app.DownloadLocation)
+		assert.Equal(t, "MIT", x.LicenseDeclared)
+		assert.Equal(t, "https://github.com/a/x", x.DownloadLocation)
+		assert.Equal(t, "Apache-2.0 OR MIT", z.LicenseDeclared)
+		assert.Equal(t, []SPDXExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:golang/github.com/a/x@v1.2.0"}}, x.ExternalRefs)
+	})
+
+	t.Run("annotations", func(t *testing.T) {
+		require.Len(t, x.Annotations, 1)
+		a := x.Annotations[0]
+		assert.Equal(t, "OTHER", a.AnnotationType)
+		assert.Equal(t, "Tool: import-graph", a.Annotator)
+		assert.Equal(t, "2024-03-10T12:00:00Z", a.AnnotationDate)
+		require.True(t, strings.HasPrefix(a.Comment, "import-graph: "))
+
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(a.Comment, "import-graph: ")), &fields))
+		assert.Equal(t, true, fields["can_get_github"])
+		assert.Equal(t, 90.0, fields["health_score"])
+		assert.Equal(t, map[string]interface{}{"git_activity": 100.0, "tests": 100.0}, fields["health_score_breakdown"])
+		assert.Equal(t, "MIT", fields["license"])
+		assert.NotContains(t, fields, "id")
+		assert.NotContains(t, fields, "version")
+	})
+
+	t.Run("relationships", func(t *testing.T) {
+		assert.Equal(t, []string{app.SPDXID}, doc.DocumentDescribes)
+		assert.Equal(t, []SPDXRelationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: app.SPDXID},
+			{SPDXElementID: app.SPDXID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: x.SPDXID},
+			{SPDXElementID: app.SPDXID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: z.SPDXID},
+			{SPDXElementID: x.SPDXID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: y.SPDXID},
+		}, doc.Relationships)
+	})
+}
@@ -0,0 +1,64 @@
+package scorecard
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// containerImages returns images that Dockerfiles in repository are built from, without stages built in same Dockerfile
+func containerImages(path string) []string {
+	var images []string
+	filepath.Walk(path, func(p string, f os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if f.IsDir() && (f.Name() == ".git" || f.Name() == "vendor" || f.Name() == "testdata") {
+			return filepath.SkipDir
+		}
+		if !f.IsDir() && isDockerfile(f.Name()) {
+			images = append(images, dockerfileImages(p)...)
+		}
+		return nil
+	})
+	return images
+}
+
+func isDockerfile(name string) bool {
+	name = strings.ToLower(name)
+	return name == "dockerfile" || strings.HasPrefix(name, "dockerfile.") || strings.HasSuffix(name, ".dockerfile")
+}
+
+// dockerfileImages returns images of FROM instructions, e.g. FROM --platform=linux/amd64 golang:1.17 AS build
+func dockerfileImages(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer func() { file.Close() }()
+
+	var images []string
+	stages := map[string]bool{"scratch": true}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		fields = fields[1:]
+		for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+			fields = fields[1:]
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		if !stages[strings.ToLower(fields[0])] {
+			images = append(images, fields[0])
+		}
+		if len(fields) == 3 && strings.EqualFold(fields[1], "AS") {
+			stages[strings.ToLower(fields[2])] = true
+		}
+	}
+	return images
+}
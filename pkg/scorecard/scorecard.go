@@ -0,0 +1,203 @@
+// Package scorecard computes subset of OpenSSF Scorecard checks from cloned repository and GitHub API
+package scorecard
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nikolaydubina/import-graph/pkg/ciscan"
+	"github.com/nikolaydubina/import-graph/pkg/github"
+)
+
+// CheckEnum is name of check, same checks as in OpenSSF Scorecard
+type CheckEnum string
+
+const (
+	CheckBranchProtection   CheckEnum = "branch_protection"
+	CheckCodeReview         CheckEnum = "code_review"
+	CheckSignedReleases     CheckEnum = "signed_releases"
+	CheckPinnedDependencies CheckEnum = "pinned_dependencies"
+	CheckDangerousWorkflow  CheckEnum = "dangerous_workflow"
+	CheckSecurityPolicy     CheckEnum = "security_policy"
+	CheckFuzzing            CheckEnum = "fuzzing"
+)
+
+// weights are by risk of check, same as in OpenSSF Scorecard: critical 10, high 7.5, medium 5
+var weights = map[CheckEnum]float64{
+	CheckDangerousWorkflow:  10,
+	CheckBranchProtection:   7.5,
+	CheckCodeReview:         7.5,
+	CheckSignedReleases:     7.5,
+	CheckPinnedDependencies: 5,
+	CheckSecurityPolicy:     5,
+	CheckFuzzing:            5,
+}
+
+// Result of check, score is from 0 to 10 and is missing when check can not tell, e.g. repository has no releases
+type Result struct {
+	Check  CheckEnum
+	Score  *int
+	Reason string
+}
+
+func scored(check CheckEnum, score int, reason string, args ...interface{}) Result {
+	return Result{Check: check, Score: &score, Reason: fmt.Sprintf(reason, args...)}
+}
+
+func inconclusive(check CheckEnum, reason string) Result {
+	return Result{Check: check, Reason: reason}
+}
+
+// ratio is score of part of total, rounded down same as in OpenSSF Scorecard
+func ratio(part, total int) int {
+	return int(math.Floor(10 * float64(part) / float64(total)))
+}
+
+// Input is what is known about repository, any part of it can be missing
+type Input struct {
+	Path         string // cloned repository, empty when it is not cloned
+	Workflows    []ciscan.Workflow
+	HasFuzzTests bool
+	Practices    *github.Practices // missing when repository is not on GitHub
+}
+
+// Run runs all checks
+func Run(in Input) []Result {
+	return []Result{
+		branchProtection(in.Practices),
+		codeReview(in.Practices),
+		signedReleases(in.Practices),
+		pinnedDependencies(in.Path, in.Workflows),
+		dangerousWorkflow(in.Path, in.Workflows),
+		securityPolicy(in.Path),
+		fuzzing(in),
+	}
+}
+
+// Score is average of scores weighted by risk of checks, missing when no check can tell
+func Score(results []Result) *float64 {
+	var sum, total float64
+	for _, r := range results {
+		if r.Score == nil {
+			continue
+		}
+		sum += weights[r.Check] * float64(*r.Score)
+		total += weights[r.Check]
+	}
+	if total == 0 {
+		return nil
+	}
+	s := math.Round(sum/total*10) / 10
+	return &s
+}
+
+func branchProtection(p *github.Practices) Result {
+	if p == nil || p.IsBranchProtected == nil {
+		return inconclusive(CheckBranchProtection, "branch protection is known only for GitHub")
+	}
+	if *p.IsBranchProtected {
+		return scored(CheckBranchProtection, 10, "default branch is protected")
+	}
+	return scored(CheckBranchProtection, 0, "default branch is not protected")
+}
+
+func codeReview(p *github.Practices) Result {
+	if p == nil || p.NumMergedPRs == nil {
+		return inconclusive(CheckCodeReview, "code review is known only for GitHub with token")
+	}
+	if *p.NumMergedPRs == 0 {
+		return inconclusive(CheckCodeReview, "no merged pull requests")
+	}
+	return scored(CheckCodeReview, ratio(p.NumReviewedPRs, *p.NumMergedPRs), "%d of %d latest merged pull requests are reviewed", p.NumReviewedPRs, *p.NumMergedPRs)
+}
+
+func signedReleases(p *github.Practices) Result {
+	if p == nil {
+		return inconclusive(CheckSignedReleases, "releases are known only for GitHub")
+	}
+	if p.NumReleases == 0 {
+		return inconclusive(CheckSignedReleases, "no releases")
+	}
+	return scored(CheckSignedReleases, ratio(p.NumSignedReleases, p.NumReleases), "%d of %d latest releases are signed", p.NumSignedReleases, p.NumReleases)
+}
+
+func pinnedDependencies(path string, workflows []ciscan.Workflow) Result {
+	if path == "" {
+		return inconclusive(CheckPinnedDependencies, "repository is not cloned")
+	}
+	var pinned, total int
+	for _, w := range workflows {
+		for _, a := range w.Actions {
+			total++
+			if ciscan.IsPinned(a) {
+				pinned++
+			}
+		}
+	}
+	for _, image := range containerImages(path) {
+		total++
+		if strings.Contains(image, "@sha256:") {
+			pinned++
+		}
+	}
+	if total == 0 {
+		return inconclusive(CheckPinnedDependencies, "no actions or container images")
+	}
+	return scored(CheckPinnedDependencies, ratio(pinned, total), "%d of %d actions and container images are pinned by hash", pinned, total)
+}
+
+func dangerousWorkflow(path string, workflows []ciscan.Workflow) Result {
+	if path == "" {
+		return inconclusive(CheckDangerousWorkflow, "repository is not cloned")
+	}
+	if len(workflows) == 0 {
+		return inconclusive(CheckDangerousWorkflow, "no workflows")
+	}
+	for _, w := range workflows {
+		if len(w.Dangerous) > 0 {
+			return scored(CheckDangerousWorkflow, 0, "%s: %s", w.Path, strings.Join(w.Dangerous, ", "))
+		}
+	}
+	return scored(CheckDangerousWorkflow, 10, "no dangerous patterns in %d workflows", len(workflows))
+}
+
+// securityPolicyPaths are where GitHub looks for security policy
+var securityPolicyPaths = []string{".", ".github", "docs"}
+
+func securityPolicy(path string) Result {
+	if path == "" {
+		return inconclusive(CheckSecurityPolicy, "repository is not cloned")
+	}
+	for _, dir := range securityPolicyPaths {
+		files, _ := ioutil.ReadDir(filepath.Join(path, dir))
+		for _, f := range files {
+			name := strings.ToLower(f.Name())
+			if !f.IsDir() && (name == "security" || strings.HasPrefix(name, "security.")) {
+				return scored(CheckSecurityPolicy, 10, "security policy is in %s", filepath.ToSlash(filepath.Join(dir, f.Name())))
+			}
+		}
+	}
+	return scored(CheckSecurityPolicy, 0, "no security policy")
+}
+
+func fuzzing(in Input) Result {
+	if in.Path == "" {
+		return inconclusive(CheckFuzzing, "repository is not cloned")
+	}
+	for _, w := range in.Workflows {
+		if w.RunsFuzzing {
+			return scored(CheckFuzzing, 10, "workflow %s runs fuzzing", w.Path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(in.Path, ".clusterfuzzlite")); err == nil {
+		return scored(CheckFuzzing, 10, "ClusterFuzzLite is set up")
+	}
+	if in.HasFuzzTests {
+		return scored(CheckFuzzing, 10, "has Go fuzz tests")
+	}
+	return scored(CheckFuzzing, 0, "no fuzzing")
+}
@@ -0,0 +1,73 @@
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikolaydubina/import-graph/pkg/ciscan"
+	"github.com/nikolaydubina/import-graph/pkg/github"
+)
+
+func TestRun(t *testing.T) {
+	protected, merged := true, 10
+	results := Run(Input{
+		Path: "testdata/repo",
+		Workflows: []ciscan.Workflow{
+			{
+				Path:    ".github/workflows/test.yml",
+				Actions: []string{"actions/checkout@a12a3943b4bdde767164f792f33f40b04645d846", "actions/setup-go@v2"},
+			},
+			{
+				Path:      ".github/workflows/pr.yml",
+				Dangerous: []string{"script injection with untrusted input"},
+			},
+		},
+		HasFuzzTests: true,
+		Practices: &github.Practices{
+			IsBranchProtected: &protected,
+			NumMergedPRs:      &merged,
+			NumReviewedPRs:    7,
+			NumReleases:       5,
+			NumSignedReleases: 0,
+		},
+	})
+
+	scores := map[CheckEnum]int{}
+	reasons := map[CheckEnum]string{}
+	for _, r := range results {
+		if assert.NotNil(t, r.Score, r.Check) {
+			scores[r.Check] = *r.Score
+		}
+		reasons[r.Check] = r.Reason
+	}
+	assert.Equal(t, map[CheckEnum]int{
+		CheckBranchProtection:   10,
+		CheckCodeReview:         7,
+		CheckSignedReleases:     0,
+		CheckPinnedDependencies: 5,
+		CheckDangerousWorkflow:  0,
+		CheckSecurityPolicy:     10,
+		CheckFuzzing:            10,
+	}, scores)
+	assert.Equal(t, "2 of 4 actions and container images are pinned by hash", reasons[CheckPinnedDependencies])
+	assert.Equal(t, ".github/workflows/pr.yml: script injection with untrusted input", reasons[CheckDangerousWorkflow])
+	assert.Equal(t, "security policy is in .github/SECURITY.md", reasons[CheckSecurityPolicy])
+
+	// (10*0 + 7.5*10 + 7.5*7 + 7.5*0 + 5*5 + 5*10 + 5*10) / 47.5 = 5.32
+	assert.Equal(t, 5.3, *Score(results))
+}
+
+func TestRunWithoutData(t *testing.T) {
+	results := Run(Input{Path: "testdata/empty"})
+	scores := map[CheckEnum]int{}
+	for _, r := range results {
+		if r.Score != nil {
+			scores[r.Check] = *r.Score
+		}
+		assert.NotEmpty(t, r.Reason)
+	}
+	assert.Equal(t, map[CheckEnum]int{CheckSecurityPolicy: 0, CheckFuzzing: 0}, scores)
+
+	assert.Nil(t, Score(Run(Input{})))
+}
@@ -0,0 +1,103 @@
+// Package sourcehut collects repository stats from git.sr.ht GraphQL API
+package sourcehut
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nikolaydubina/import-graph/pkg/hosting"
+)
+
+// Client is client of git.sr.ht
+type Client struct {
+	HTTPClient *http.Client
+	URL        string // e.g. https://git.sr.ht/query
+	Token      string // personal access token, API does not work without it
+	now        func() time.Time
+}
+
+const repositoryQuery = `query($owner: String!, $name: String!) {
+  user(username: $owner) {
+    repository(name: $name) {
+      name
+      updated
+    }
+  }
+}`
+
+type response struct {
+	Data struct {
+		User *struct {
+			Repository *struct {
+				Name    string     `json:"name"`
+				Updated *time.Time `json:"updated"`
+			} `json:"repository"`
+		} `json:"user"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// GetSummary collects summary about repository.
+// SourceHut does not have stars, forks and archived repositories, and issues are in separate todo.sr.ht trackers.
+func (c Client) GetSummary(ctx context.Context, repoURL url.URL) (*hosting.Summary, error) {
+	owner, name := hosting.RepoPath(repoURL)
+	if owner == "" {
+		return nil, fmt.Errorf("can not parse SourceHut URL %s", repoURL.String())
+	}
+	if c.Token == "" {
+		return nil, errors.New("sourcehut token is not set")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     repositoryQuery,
+		"variables": map[string]string{"owner": strings.TrimPrefix(owner, "~"), "name": name},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("can not make request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("can not make request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var r response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("can not decode response: %w", err)
+	}
+	if len(r.Errors) > 0 {
+		return nil, fmt.Errorf("can not get sourcehut repository %s/%s: %s", owner, name, r.Errors[0].Message)
+	}
+	if r.Data.User == nil || r.Data.User.Repository == nil {
+		return nil, fmt.Errorf("sourcehut repository %s/%s is not found", owner, name)
+	}
+
+	s := hosting.Summary{
+		Provider: hosting.ProviderSourceHut,
+		URL:      "https://" + repoURL.Host + "/" + owner + "/" + name,
+	}
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+	s.SetLastPush(r.Data.User.Repository.Updated, now)
+	return &s, nil
+}
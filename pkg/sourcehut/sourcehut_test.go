@@ -0,0 +1,68 @@
+package sourcehut
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/hosting"
+)
+
+func TestGetSummary(t *testing.T) {
+	responses := map[string][]byte{}
+	for _, name := range []string{"repository", "not_found"} {
+		b, err := ioutil.ReadFile("testdata/" + name + "_response.json")
+		require.NoError(t, err)
+		responses[name] = b
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var req struct {
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if req.Variables["owner"] == "sircmpwn" && req.Variables["name"] == "scdoc" {
+			w.Write(responses["repository"])
+			return
+		}
+		w.Write(responses["not_found"])
+	}))
+	defer server.Close()
+
+	client := Client{
+		HTTPClient: server.Client(),
+		URL:        server.URL + "/query",
+		Token:      "secret",
+		now:        func() time.Time { return time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC) },
+	}
+
+	s, err := client.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "git.sr.ht", Path: "/~sircmpwn/scdoc"})
+	require.NoError(t, err)
+
+	pushed := time.Date(2021, 8, 30, 12, 1, 44, 293877000, time.UTC)
+	var days uint = 31
+	assert.Equal(t, &hosting.Summary{
+		Provider:     hosting.ProviderSourceHut,
+		URL:          "https://git.sr.ht/~sircmpwn/scdoc",
+		LastPushAt:   &pushed,
+		LastPushDays: &days,
+	}, s)
+
+	_, err = client.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "git.sr.ht", Path: "/~someone/missing"})
+	assert.Error(t, err)
+
+	_, err = Client{HTTPClient: server.Client(), URL: server.URL + "/query"}.GetSummary(context.Background(), url.URL{Scheme: "https", Host: "git.sr.ht", Path: "/~sircmpwn/scdoc"})
+	assert.Error(t, err)
+}
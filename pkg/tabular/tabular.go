@@ -0,0 +1,111 @@
+// Package tabular writes collected graph as tables with one row per module
+package tabular
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+// FormatEnum is table format
+type FormatEnum string
+
+const (
+	FormatCSV      FormatEnum = "csv"
+	FormatTSV      FormatEnum = "tsv"
+	FormatMarkdown FormatEnum = "md"
+)
+
+// Writer writes modules and edges as tables
+type Writer struct {
+	Format  FormatEnum
+	Columns []string // columns to write, by default all columns that have values in any module
+}
+
+// WriteModules writes one row per module.
+// Default columns are in same order as fields in JSONL.
+func (c Writer) WriteModules(w io.Writer, g collector.Graph) error {
+	rows, err := g.ModuleFields()
+	if err != nil {
+		return err
+	}
+
+	columns := c.Columns
+	if len(columns) == 0 {
+		columns = collector.PresentColumns(rows)
+	}
+
+	table := [][]string{columns}
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			cells[i] = formatValue(row[col])
+		}
+		table = append(table, cells)
+	}
+	return c.write(w, table)
+}
+
+// WriteEdges writes one row per edge
+func (c Writer) WriteEdges(w io.Writer, g collector.Graph) error {
+	table := [][]string{{"from", "to"}}
+	for _, e := range g.Edges {
+		table = append(table, []string{e.From, e.To})
+	}
+	return c.write(w, table)
+}
+
+func (c Writer) write(w io.Writer, table [][]string) error {
+	switch c.Format {
+	case FormatCSV, FormatTSV:
+		cw := csv.NewWriter(w)
+		if c.Format == FormatTSV {
+			cw.Comma = '\t'
+		}
+		if err := cw.WriteAll(table); err != nil {
+			return fmt.Errorf("can not write %s: %w", c.Format, err)
+		}
+		return nil
+	case FormatMarkdown:
+		return writeMarkdown(w, table)
+	default:
+		return fmt.Errorf("unknown table format %s", c.Format)
+	}
+}
+
+func writeMarkdown(w io.Writer, table [][]string) error {
+	var b strings.Builder
+	for i, row := range table {
+		cells := make([]string, len(row))
+		for j, v := range row {
+			cells[j] = strings.ReplaceAll(strings.ReplaceAll(v, "|", `\|`), "\n", " ")
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", len(row)) + "\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatValue(v interface{}) string {
+	switch vv := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return vv
+	case bool:
+		return strconv.FormatBool(vv)
+	case float64:
+		return strconv.FormatFloat(vv, 'f', -1, 64)
+	default:
+		b, _ := json.Marshal(vv)
+		return string(b)
+	}
+}
@@ -0,0 +1,39 @@
+package tabular
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+func TestWriter(t *testing.T) {
+	g := collector.Graph{
+		Modules: []collector.ModuleStats{
+			{ID: "a", CanGetGitStats: true, GitStats: &collector.GitStats{LastCommit: "2021-04-21", NumContributors: 3}},
+			{ID: "b|c"},
+		},
+		Edges: []collector.Edge{{From: "a", To: "b|c"}},
+	}
+
+	t.Run("csv with selected columns", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, Writer{Format: FormatCSV, Columns: []string{"id", "git_num_contributors"}}.WriteModules(&b, g))
+		assert.Equal(t, "id,git_num_contributors\na,3\nb|c,\n", b.String())
+	})
+
+	t.Run("tsv default columns are in order of declaration", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, Writer{Format: FormatTSV}.WriteModules(&b, g))
+		assert.Equal(t, "id\tcan_get_git\tcan_run_tests\tcan_get_github\tgit_last_commit\tgit_last_commit_days_since\tgit_num_contributors\tgit_num_releases_last_year\na\ttrue\tfalse\tfalse\t2021-04-21\t0\t3\t0\nb|c\tfalse\tfalse\tfalse\t\t\t\t\n", b.String())
+	})
+
+	t.Run("markdown edges", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, Writer{Format: FormatMarkdown}.WriteEdges(&b, g))
+		assert.Equal(t, "| from | to |\n| --- | --- |\n| a | b\\|c |\n", b.String())
+	})
+}
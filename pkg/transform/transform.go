@@ -0,0 +1,289 @@
+// Package transform changes structure of graph to keep only what matters, before collecting stats or writing output
+package transform
+
+import (
+	"regexp"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// Transform returns new graph from graph
+type Transform interface {
+	Apply(g gomodgraph.Graph) gomodgraph.Graph
+}
+
+// Pipeline applies transforms in order
+type Pipeline []Transform
+
+// Apply applies all transforms
+func (p Pipeline) Apply(g gomodgraph.Graph) gomodgraph.Graph {
+	for _, t := range p {
+		g = t.Apply(g)
+	}
+	return g
+}
+
+// StdAdjacent matches modules maintained with Go itself and pseudo-modules of go directive in go mod graph
+var StdAdjacent = regexp.MustCompile(`^(golang\.org/x/|go$|toolchain$|std$|cmd$)`)
+
+// MaxDepth keeps modules within distance from main module
+type MaxDepth struct {
+	Depth uint
+}
+
+// Apply keeps modules reachable from main module in at most Depth edges
+func (t MaxDepth) Apply(g gomodgraph.Graph) gomodgraph.Graph {
+	root := g.Root()
+	depth := map[string]uint{root: 0}
+	queue := []string{root}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		if depth[curr] >= t.Depth {
+			continue
+		}
+		for _, next := range g.Children(curr) {
+			if _, ok := depth[next]; !ok {
+				depth[next] = depth[curr] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	keep := make(map[string]bool, len(depth))
+	for m := range depth {
+		keep[m] = true
+	}
+	return subgraph(g, keep)
+}
+
+// Focus keeps modules that match pattern and modules on paths from main module to them
+type Focus struct {
+	Pattern *regexp.Regexp
+}
+
+// Apply keeps matching modules and their dependents that are reachable from main module
+func (t Focus) Apply(g gomodgraph.Graph) gomodgraph.Graph {
+	parents := map[string][]string{}
+	for _, e := range g.Edges {
+		parents[e.To] = append(parents[e.To], e.From)
+	}
+
+	root := g.Root()
+	reachable := g.Reachable(root)
+
+	keep := map[string]bool{}
+	var queue []string
+	for _, n := range g.Modules {
+		if t.Pattern.MatchString(n.ModuleName) && reachable[n.ModuleName] && !keep[n.ModuleName] {
+			keep[n.ModuleName] = true
+			queue = append(queue, n.ModuleName)
+		}
+	}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, prev := range parents[curr] {
+			if !keep[prev] && reachable[prev] {
+				keep[prev] = true
+				queue = append(queue, prev)
+			}
+		}
+	}
+	if len(keep) > 0 {
+		keep[root] = true
+	}
+	return subgraph(g, keep)
+}
+
+// Drop removes modules that match pattern and modules that are no longer reachable from main module.
+// Main module is never removed.
+type Drop struct {
+	Pattern *regexp.Regexp
+}
+
+// Apply removes matching modules
+func (t Drop) Apply(g gomodgraph.Graph) gomodgraph.Graph {
+	root := g.Root()
+	keep := map[string]bool{}
+	for _, n := range g.Modules {
+		keep[n.ModuleName] = n.ModuleName == root || !t.Pattern.MatchString(n.ModuleName)
+	}
+	g = subgraph(g, keep)
+	return subgraph(g, g.Reachable(root))
+}
+
+// CollapseByOwner merges modules of same repository owner into single node named by owner, e.g. github.com/gin-gonic.
+// Main module is kept as is. Merged node has version only if it is single module.
+type CollapseByOwner struct{}
+
+// Apply merges modules
+func (t CollapseByOwner) Apply(g gomodgraph.Graph) gomodgraph.Graph {
+	root := g.Root()
+	name := func(m string) string {
+		if m == root {
+			return m
+		}
+		return gomodgraph.Owner(m)
+	}
+
+	var out gomodgraph.Graph
+	index := map[string]int{}
+	for _, n := range g.Modules {
+		to := name(n.ModuleName)
+		if i, ok := index[to]; ok {
+			out.Modules[i] = gomodgraph.Node{ModuleName: to}
+			continue
+		}
+		index[to] = len(out.Modules)
+		if to != n.ModuleName {
+			n = gomodgraph.Node{ModuleName: to, Version: n.Version}
+		}
+		out.Modules = append(out.Modules, n)
+	}
+
+	seen := map[gomodgraph.Edge]bool{}
+	for _, e := range g.Edges {
+		ne := gomodgraph.Edge{From: name(e.From), To: name(e.To)}
+		if ne.From == ne.To || seen[ne] {
+			continue
+		}
+		seen[ne] = true
+		out.Edges = append(out.Edges, ne)
+	}
+	return out
+}
+
+// TransitiveReduction removes edges that are implied by other paths, e.g. a -> c when a -> b -> c.
+// Reachability between modules stays same.
+// Modules in cycle reach each other, so edges within cycle are kept, and of edges between same two cycles only first is kept.
+type TransitiveReduction struct{}
+
+// Apply removes redundant edges of graph where every cycle is collapsed into single node, which has no cycles
+func (t TransitiveReduction) Apply(g gomodgraph.Graph) gomodgraph.Graph {
+	comp := components(g)
+
+	adj := map[string][]string{}
+	seen := map[gomodgraph.Edge]bool{}
+	for _, e := range g.Edges {
+		ce := gomodgraph.Edge{From: comp[e.From], To: comp[e.To]}
+		if ce.From == ce.To || seen[ce] {
+			continue
+		}
+		seen[ce] = true
+		adj[ce.From] = append(adj[ce.From], ce.To)
+	}
+
+	out := gomodgraph.Graph{Modules: g.Modules}
+	kept := map[gomodgraph.Edge]bool{}
+	for _, e := range g.Edges {
+		ce := gomodgraph.Edge{From: comp[e.From], To: comp[e.To]}
+		switch {
+		case ce.From == ce.To:
+			out.Edges = append(out.Edges, e)
+		case !kept[ce] && !reachableWithout(adj, ce):
+			kept[ce] = true
+			out.Edges = append(out.Edges, e)
+		}
+	}
+	return out
+}
+
+// components returns strongly connected component of every module, named by one of its modules, by Tarjan's algorithm
+func components(g gomodgraph.Graph) map[string]string {
+	adj := map[string][]string{}
+	var modules []string
+	for _, n := range g.Modules {
+		modules = append(modules, n.ModuleName)
+	}
+	for _, e := range g.Edges {
+		adj[e.From] = append(adj[e.From], e.To)
+		modules = append(modules, e.From, e.To)
+	}
+
+	comp := map[string]string{}
+	index := map[string]int{}
+	low := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+
+	var visit func(m string)
+	visit = func(m string) {
+		index[m] = len(index)
+		low[m] = index[m]
+		stack = append(stack, m)
+		onStack[m] = true
+
+		for _, next := range adj[m] {
+			if _, ok := index[next]; !ok {
+				visit(next)
+				if low[next] < low[m] {
+					low[m] = low[next]
+				}
+			} else if onStack[next] && index[next] < low[m] {
+				low[m] = index[next]
+			}
+		}
+
+		if low[m] == index[m] {
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				comp[top] = m
+				if top == m {
+					break
+				}
+			}
+		}
+	}
+
+	for _, m := range modules {
+		if _, ok := index[m]; !ok {
+			visit(m)
+		}
+	}
+	return comp
+}
+
+// reachableWithout checks if target of edge is reachable from its source without that edge
+func reachableWithout(adj map[string][]string, edge gomodgraph.Edge) bool {
+	visited := map[string]bool{edge.From: true}
+	var queue []string
+	for _, next := range adj[edge.From] {
+		if next != edge.To && !visited[next] {
+			visited[next] = true
+			queue = append(queue, next)
+		}
+	}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		if curr == edge.To {
+			return true
+		}
+		for _, next := range adj[curr] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// subgraph keeps modules and edges between them, in same order
+func subgraph(g gomodgraph.Graph, keep map[string]bool) gomodgraph.Graph {
+	var out gomodgraph.Graph
+	for _, n := range g.Modules {
+		if keep[n.ModuleName] {
+			out.Modules = append(out.Modules, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			out.Edges = append(out.Edges, e)
+		}
+	}
+	return out
+}
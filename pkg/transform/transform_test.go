@@ -0,0 +1,145 @@
+package transform
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikolaydubina/import-graph/pkg/gomodgraph"
+)
+
+// root -> github.com/a/x -> github.com/b/y -> golang.org/x/sys
+// root -> github.com/a/z -> github.com/b/y
+// root -> github.com/b/y
+// root -> golang.org/x/text -> github.com/c/w
+func testGraph() gomodgraph.Graph {
+	return gomodgraph.Graph{
+		Modules: []gomodgraph.Node{
+			{ModuleName: "root"},
+			{ModuleName: "github.com/a/x", Version: "v1.0.0"},
+			{ModuleName: "github.com/a/z", Version: "v1.1.0"},
+			{ModuleName: "github.com/b/y", Version: "v0.1.0"},
+			{ModuleName: "golang.org/x/sys", Version: "v0.0.1"},
+			{ModuleName: "golang.org/x/text", Version: "v0.3.0"},
+			{ModuleName: "github.com/c/w", Version: "v2.0.0"},
+		},
+		Edges: []gomodgraph.Edge{
+			{From: "root", To: "github.com/a/x"},
+			{From: "root", To: "github.com/a/z"},
+			{From: "root", To: "github.com/b/y"},
+			{From: "root", To: "golang.org/x/text"},
+			{From: "github.com/a/x", To: "github.com/b/y"},
+			{From: "github.com/a/z", To: "github.com/b/y"},
+			{From: "github.com/b/y", To: "golang.org/x/sys"},
+			{From: "golang.org/x/text", To: "github.com/c/w"},
+		},
+	}
+}
+
+func names(g gomodgraph.Graph) []string {
+	var ns []string
+	for _, n := range g.Modules {
+		ns = append(ns, n.ModuleName)
+	}
+	return ns
+}
+
+func TestMaxDepth(t *testing.T) {
+	g := MaxDepth{Depth: 1}.Apply(testGraph())
+	assert.Equal(t, []string{"root", "github.com/a/x", "github.com/a/z", "github.com/b/y", "golang.org/x/text"}, names(g))
+	assert.Len(t, g.Edges, 6)
+}
+
+func TestFocus(t *testing.T) {
+	g := Focus{Pattern: regexp.MustCompile(`^golang.org/x/sys$`)}.Apply(testGraph())
+	assert.Equal(t, []string{"root", "github.com/a/x", "github.com/a/z", "github.com/b/y", "golang.org/x/sys"}, names(g))
+
+	g = Focus{Pattern: regexp.MustCompile(`nothing`)}.Apply(testGraph())
+	assert.Empty(t, g.Modules)
+}
+
+func TestDropStd(t *testing.T) {
+	g := Drop{Pattern: StdAdjacent}.Apply(testGraph())
+	// github.com/c/w is only reachable through golang.org/x/text
+	assert.Equal(t, []string{"root", "github.com/a/x", "github.com/a/z", "github.com/b/y"}, names(g))
+	assert.Len(t, g.Edges, 5)
+}
+
+func TestCollapseByOwner(t *testing.T) {
+	g := CollapseByOwner{}.Apply(testGraph())
+	assert.Equal(t, []gomodgraph.Node{
+		{ModuleName: "root"},
+		{ModuleName: "github.com/a"},
+		{ModuleName: "github.com/b", Version: "v0.1.0"},
+		{ModuleName: "golang.org/x"},
+		{ModuleName: "github.com/c", Version: "v2.0.0"},
+	}, g.Modules)
+	assert.Equal(t, []gomodgraph.Edge{
+		{From: "root", To: "github.com/a"},
+		{From: "root", To: "github.com/b"},
+		{From: "root", To: "golang.org/x"},
+		{From: "github.com/a", To: "github.com/b"},
+		{From: "github.com/b", To: "golang.org/x"},
+		{From: "golang.org/x", To: "github.com/c"},
+	}, g.Edges)
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	g := TransitiveReduction{}.Apply(testGraph())
+	assert.NotContains(t, g.Edges, gomodgraph.Edge{From: "root", To: "github.com/b/y"})
+	assert.Len(t, g.Edges, 7)
+	assert.Equal(t, testGraph().Modules, g.Modules)
+}
+
+func TestTransitiveReductionCycle(t *testing.T) {
+	// root -> a <-> b, a -> c, b -> c, c -> d, a -> d
+	g := gomodgraph.Graph{
+		Modules: []gomodgraph.Node{{ModuleName: "root"}, {ModuleName: "a"}, {ModuleName: "b"}, {ModuleName: "c"}, {ModuleName: "d"}},
+		Edges: []gomodgraph.Edge{
+			{From: "root", To: "a"},
+			{From: "a", To: "b"},
+			{From: "b", To: "a"},
+			{From: "a", To: "c"},
+			{From: "b", To: "c"},
+			{From: "c", To: "d"},
+			{From: "a", To: "d"},
+		},
+	}
+	reduced := TransitiveReduction{}.Apply(g)
+	assert.Equal(t, []gomodgraph.Edge{
+		{From: "root", To: "a"},
+		{From: "a", To: "b"},
+		{From: "b", To: "a"},
+		{From: "a", To: "c"},
+		{From: "c", To: "d"},
+	}, reduced.Edges)
+	for _, m := range []string{"a", "b", "c", "d"} {
+		assert.Equal(t, g.Reachable(m), reduced.Reachable(m), m)
+	}
+}
+
+func TestCollapseByOwnerTwoPartPaths(t *testing.T) {
+	g := gomodgraph.Graph{
+		Modules: []gomodgraph.Node{
+			{ModuleName: "root"},
+			{ModuleName: "gopkg.in/yaml.v3", Version: "v3.0.1"},
+			{ModuleName: "gopkg.in/check.v1", Version: "v1.0.0"},
+		},
+		Edges: []gomodgraph.Edge{
+			{From: "root", To: "gopkg.in/yaml.v3"},
+			{From: "gopkg.in/yaml.v3", To: "gopkg.in/check.v1"},
+		},
+	}
+	assert.Equal(t, g, CollapseByOwner{}.Apply(g))
+}
+
+func TestPipeline(t *testing.T) {
+	g := Pipeline{Drop{Pattern: StdAdjacent}, TransitiveReduction{}}.Apply(testGraph())
+	assert.Equal(t, []gomodgraph.Edge{
+		{From: "root", To: "github.com/a/x"},
+		{From: "root", To: "github.com/a/z"},
+		{From: "github.com/a/x", To: "github.com/b/y"},
+		{From: "github.com/a/z", To: "github.com/b/y"},
+	}, g.Edges)
+}
@@ -0,0 +1,168 @@
+// Package why explains how module enters graph by listing dependency paths from main module to it
+package why
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+// Hop is module on path
+type Hop struct {
+	Module  string                 `json:"module"`
+	Version string                 `json:"version,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// DirectDependency is direct dependency of main module through which target is reachable
+type DirectDependency struct {
+	Module   string `json:"module"`
+	NumPaths int    `json:"num_paths"` // number of listed paths that go through it
+}
+
+// Result is all paths to module
+type Result struct {
+	Module    string             `json:"module"`
+	Version   string             `json:"version,omitempty"`
+	Via       []DirectDependency `json:"via"`
+	Paths     [][]Hop            `json:"paths"`
+	Truncated bool               `json:"truncated"` // there are more paths than listed
+}
+
+// MaxPaths is max number of paths listed when all paths are requested, since number of all paths can be exponential in size of graph
+const MaxPaths = 1000
+
+// Query finds paths from main module
+type Query struct {
+	K      int      // max number of shortest paths, zero is all paths up to MaxPaths
+	Fields []string // fields of modules to show on each hop
+}
+
+// Run finds paths from main module to target
+func (q Query) Run(g collector.Graph, target string) (Result, error) {
+	result := Result{Module: target, Paths: [][]Hop{}, Via: []DirectDependency{}}
+	k := q.K
+	if k < 0 {
+		return result, errors.New("number of paths can not be negative")
+	}
+	if k == 0 {
+		k = MaxPaths
+	}
+
+	rows, err := g.ModuleFields()
+	if err != nil {
+		return result, err
+	}
+	fields := make(map[string]map[string]interface{}, len(rows))
+	for _, row := range rows {
+		if id, ok := row["id"].(string); ok {
+			fields[id] = row
+		}
+	}
+	if _, ok := fields[target]; !ok {
+		return result, fmt.Errorf("module %s is not in graph", target)
+	}
+	result.Version, _ = fields[target]["version"].(string)
+
+	gmod := g.GoModGraph()
+	// one more path tells that there are more paths than listed
+	paths := gmod.Paths(gmod.Root(), target, k+1)
+	if len(paths) > k {
+		paths, result.Truncated = paths[:k], true
+	}
+
+	numPaths := map[string]int{}
+	for _, path := range paths {
+		if len(path) > 1 {
+			numPaths[path[1]]++
+		}
+		hops := make([]Hop, 0, len(path))
+		for _, m := range path {
+			hop := Hop{Module: m}
+			hop.Version, _ = fields[m]["version"].(string)
+			for _, f := range q.Fields {
+				if v, ok := fields[m][f]; ok && v != nil {
+					if hop.Fields == nil {
+						hop.Fields = map[string]interface{}{}
+					}
+					hop.Fields[f] = v
+				}
+			}
+			hops = append(hops, hop)
+		}
+		result.Paths = append(result.Paths, hops)
+	}
+
+	for _, d := range gmod.DirectDependenciesReaching(target) {
+		result.Via = append(result.Via, DirectDependency{Module: d, NumPaths: numPaths[d]})
+	}
+	sort.SliceStable(result.Via, func(i, j int) bool { return result.Via[i].NumPaths > result.Via[j].NumPaths })
+
+	return result, nil
+}
+
+// WriteJSON writes result as single JSON object
+func (r Result) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteText writes result for humans, every path as indented list of hops
+func (r Result) WriteText(w io.Writer) error {
+	var b strings.Builder
+	if len(r.Paths) == 0 {
+		fmt.Fprintf(&b, "%s is not reachable from main module\n", versioned(r.Module, r.Version))
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	fmt.Fprintf(&b, "%s is pulled in by %d direct dependencies:\n", versioned(r.Module, r.Version), len(r.Via))
+	for _, d := range r.Via {
+		fmt.Fprintf(&b, "\t%s (listed paths: %d)\n", d.Module, d.NumPaths)
+	}
+
+	more := ""
+	if r.Truncated {
+		more = ", there are more"
+	}
+	fmt.Fprintf(&b, "\n%d paths%s:\n", len(r.Paths), more)
+	for i, path := range r.Paths {
+		fmt.Fprintf(&b, "\n#%d\n", i+1)
+		for depth, hop := range path {
+			fmt.Fprintf(&b, "%s%s%s\n", strings.Repeat("  ", depth), versioned(hop.Module, hop.Version), formatFields(hop.Fields))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func versioned(module, version string) string {
+	if version == "" {
+		return module
+	}
+	return module + "@" + version
+}
+
+func formatFields(fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s: %v", k, fields[k]))
+	}
+	return " [" + strings.Join(parts, ", ") + "]"
+}
@@ -0,0 +1,99 @@
+package why
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikolaydubina/import-graph/pkg/collector"
+)
+
+func graph() collector.Graph {
+	// root -> a -> c -> d
+	// root -> b -> d
+	// root -> b -> c
+	return collector.Graph{
+		Modules: []collector.ModuleStats{
+			{ID: "root"},
+			{ID: "a", Version: "v1.0.0", HealthStats: &collector.HealthStats{Score: 80}},
+			{ID: "b", Version: "v1.1.0"},
+			{ID: "c", Version: "v0.2.0"},
+			{ID: "d", Version: "v2.0.0", HealthStats: &collector.HealthStats{Score: 50}},
+		},
+		Edges: []collector.Edge{
+			{From: "root", To: "a"},
+			{From: "root", To: "b"},
+			{From: "a", To: "c"},
+			{From: "b", To: "d"},
+			{From: "b", To: "c"},
+			{From: "c", To: "d"},
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	q := Query{K: 5, Fields: []string{"health_score"}}
+	result, err := q.Run(graph(), "d")
+	require.NoError(t, err)
+
+	assert.Equal(t, Result{
+		Module:  "d",
+		Version: "v2.0.0",
+		Via:     []DirectDependency{{Module: "b", NumPaths: 2}, {Module: "a", NumPaths: 1}},
+		Paths: [][]Hop{
+			{{Module: "root"}, {Module: "b", Version: "v1.1.0"}, {Module: "d", Version: "v2.0.0", Fields: map[string]interface{}{"health_score": 50.0}}},
+			{{Module: "root"}, {Module: "a", Version: "v1.0.0", Fields: map[string]interface{}{"health_score": 80.0}}, {Module: "c", Version: "v0.2.0"}, {Module: "d", Version: "v2.0.0", Fields: map[string]interface{}{"health_score": 50.0}}},
+			{{Module: "root"}, {Module: "b", Version: "v1.1.0"}, {Module: "c", Version: "v0.2.0"}, {Module: "d", Version: "v2.0.0", Fields: map[string]interface{}{"health_score": 50.0}}},
+		},
+	}, result)
+}
+
+func TestRunTruncated(t *testing.T) {
+	result, err := Query{K: 1}.Run(graph(), "d")
+	require.NoError(t, err)
+
+	assert.True(t, result.Truncated)
+	assert.Len(t, result.Paths, 1)
+	assert.Equal(t, []DirectDependency{{Module: "b", NumPaths: 1}, {Module: "a", NumPaths: 0}}, result.Via)
+
+	var b bytes.Buffer
+	require.NoError(t, result.WriteText(&b))
+	assert.Equal(t, `d@v2.0.0 is pulled in by 2 direct dependencies:
+	b (listed paths: 1)
+	a (listed paths: 0)
+
+1 paths, there are more:
+
+#1
+root
+  b@v1.1.0
+    d@v2.0.0
+`, b.String())
+}
+
+func TestRunAllPaths(t *testing.T) {
+	result, err := Query{}.Run(graph(), "d")
+	require.NoError(t, err)
+	assert.False(t, result.Truncated)
+	assert.Len(t, result.Paths, 3)
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Run("not in graph", func(t *testing.T) {
+		_, err := Query{K: 5}.Run(graph(), "x")
+		assert.Error(t, err)
+	})
+
+	t.Run("negative number of paths", func(t *testing.T) {
+		_, err := Query{K: -1}.Run(graph(), "d")
+		assert.Error(t, err)
+	})
+}
+
+func TestWriteTextNotReachable(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, Result{Module: "x", Version: "v1.0.0"}.WriteText(&b))
+	assert.Equal(t, "x@v1.0.0 is not reachable from main module\n", b.String())
+}